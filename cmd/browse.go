@@ -0,0 +1,432 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/vdjagilev/nmap-formatter/v3/formatter"
+)
+
+// browseHelp is a list of key bindings displayed at the bottom of the screen
+const browseHelp = "[yellow]/[-] search  [yellow]f[-] filter  [yellow]space[-] mark  [yellow]e[-] export  [yellow]tab[-] switch pane  [yellow]q[-] quit"
+
+// browseCmd opens interactive terminal UI to browse scan results
+var browseCmd = &cobra.Command{
+	Use:   "browse [path-to-nmap.xml]",
+	Short: "Interactive terminal UI to browse scan results",
+	Long: `Opens full-screen terminal UI with a list of hosts and details (ports, scripts, OS) of the selected host.
+Hosts can be searched incrementally (/), filtered with the same expressions as --filter flag (f),
+marked (space) and exported to any output format (e), for example: "html report.html".
+Marked hosts are exported, or all hosts in the list if nothing is marked`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: browse,
+}
+
+func init() {
+	rootCmd.AddCommand(browseCmd)
+}
+
+// addBrowseFlags shares root command flags with browse subcommand, so filters and
+// output options can be used while exporting, output file is chosen in the UI
+func addBrowseFlags() {
+	rootCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name != "file" && f.Name != "version" {
+			browseCmd.Flags().AddFlag(f)
+		}
+	})
+}
+
+// browser is an interactive terminal UI that displays hosts of the scan
+type browser struct {
+	app     *tview.Application
+	hosts   *tview.List
+	details *tview.TextView
+	search  *tview.InputField
+	filter  *tview.InputField
+	export  *tview.InputField
+	status  *tview.TextView
+
+	config formatter.Config
+	// run contains all hosts, default filters (like --skip-down-hosts) are already applied
+	run formatter.NMAPRun
+	// filtered contains hosts that match filter expression
+	filtered formatter.NMAPRun
+	// visible contains indexes of filtered hosts that match search query, in the same order as in the list
+	visible []int
+	// marked contains addresses of the hosts that are marked for export
+	marked map[string]bool
+}
+
+// browse parses input file and runs terminal UI until user quits
+func browse(cmd *cobra.Command, args []string) error {
+	config.InputFileConfig = formatter.InputFileConfig{}
+	if len(args) > 0 {
+		config.InputFileConfig.Path = args[0]
+		if err := config.InputFileConfig.ExistsOpen(); err != nil {
+			return fmt.Errorf("could not open XML file: %v", err)
+		}
+	} else {
+		config.InputFileConfig.IsStdin = true
+	}
+
+	w := &formatter.MainWorkflow{}
+	w.SetConfig(&config)
+	w.SetInputFile()
+	run, err := formatter.ParseNMAPRun(config.InputFileConfig.Source)
+	_ = config.InputFileConfig.Source.Close()
+	if err != nil {
+		return err
+	}
+
+	b, err := newBrowser(run, config)
+	if err != nil {
+		return err
+	}
+	// Log entries would break the screen, they are discarded while UI is running
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	return b.app.Run()
+}
+
+// newBrowser creates terminal UI, filter expressions from the config are used as initial filter
+func newBrowser(run formatter.NMAPRun, c formatter.Config) (*browser, error) {
+	var err error
+	if c.SkipDownHosts {
+		run, err = formatter.FilterNMAPRun(run, []string{formatter.SkipDownHostsExpression})
+		if err != nil {
+			return nil, err
+		}
+	}
+	b := &browser{
+		app:     tview.NewApplication(),
+		hosts:   tview.NewList(),
+		details: tview.NewTextView(),
+		search:  tview.NewInputField(),
+		filter:  tview.NewInputField(),
+		export:  tview.NewInputField(),
+		status:  tview.NewTextView(),
+		config:  c,
+		run:     run,
+		marked:  map[string]bool{},
+	}
+
+	b.hosts.ShowSecondaryText(false).SetHighlightFullLine(true).SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		b.showHost(index)
+	})
+	b.hosts.SetBorder(true)
+	b.details.SetDynamicColors(true).SetWrap(true).SetBorder(true)
+
+	b.search.SetLabel("Search: ").SetChangedFunc(func(string) { b.refresh() })
+	b.search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			b.search.SetText("")
+		}
+		b.app.SetFocus(b.hosts)
+	})
+	b.filter.SetLabel("Filter: ").SetText(joinExpressions(c.FilterExpressions)).SetChangedFunc(func(text string) {
+		if err := b.applyFilter(text); err != nil {
+			b.message(err.Error(), true)
+		} else {
+			b.message("", false)
+		}
+	})
+	b.filter.SetDoneFunc(func(tcell.Key) { b.app.SetFocus(b.hosts) })
+	b.export.SetLabel("Export: ").SetPlaceholder("format path, e.g.: html report.html")
+	b.export.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			if err := b.exportSelection(b.export.GetText()); err != nil {
+				b.message(err.Error(), true)
+			} else {
+				b.message(fmt.Sprintf("exported to %s", b.export.GetText()), false)
+				b.export.SetText("")
+			}
+		}
+		b.app.SetFocus(b.hosts)
+	})
+	b.status.SetDynamicColors(true).SetText(browseHelp)
+
+	if err = b.applyFilter(b.filter.GetText()); err != nil {
+		return nil, fmt.Errorf("error filtering: %v", err)
+	}
+
+	panes := tview.NewFlex().
+		AddItem(b.hosts, 0, 1, true).
+		AddItem(b.details, 0, 2, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(b.search, 1, 0, false).
+		AddItem(b.filter, 1, 0, false).
+		AddItem(b.export, 1, 0, false).
+		AddItem(b.status, 1, 0, false)
+	b.app.SetRoot(layout, true).SetFocus(b.hosts).SetInputCapture(b.handleKey)
+	return b, nil
+}
+
+// joinExpressions joins multiple filter expressions into one
+func joinExpressions(expressions []string) string {
+	if len(expressions) == 1 {
+		return expressions[0]
+	}
+	parts := make([]string, len(expressions))
+	for i, e := range expressions {
+		parts[i] = "(" + e + ")"
+	}
+	return strings.Join(parts, " && ")
+}
+
+// handleKey handles global key bindings, keys are passed to input fields when they are focused
+func (b *browser) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	focus := b.app.GetFocus()
+	if focus != b.hosts && focus != b.details {
+		return event
+	}
+	switch event.Key() {
+	case tcell.KeyTab, tcell.KeyBacktab:
+		if focus == b.hosts {
+			b.app.SetFocus(b.details)
+		} else {
+			b.app.SetFocus(b.hosts)
+		}
+		return nil
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'q':
+			b.app.Stop()
+		case '/':
+			b.app.SetFocus(b.search)
+		case 'f':
+			b.app.SetFocus(b.filter)
+		case 'e':
+			b.app.SetFocus(b.export)
+		case ' ':
+			b.toggleMark()
+		default:
+			return event
+		}
+		return nil
+	}
+	return event
+}
+
+// applyFilter filters hosts with the expression, previous results are kept if expression is not valid
+func (b *browser) applyFilter(expression string) error {
+	filtered := b.run
+	if strings.TrimSpace(expression) != "" {
+		var err error
+		filtered, err = formatter.FilterNMAPRun(b.run, []string{expression})
+		if err != nil {
+			return err
+		}
+	}
+	b.filtered = filtered
+	b.refresh()
+	return nil
+}
+
+// refresh rebuilds the list of hosts that match filter expression and search query
+func (b *browser) refresh() {
+	query := strings.ToLower(strings.TrimSpace(b.search.GetText()))
+	b.visible = b.visible[:0]
+	b.hosts.Clear()
+	for i := range b.filtered.Host {
+		h := &b.filtered.Host[i]
+		if query != "" && !strings.Contains(hostSearchText(h), query) {
+			continue
+		}
+		// Index is added before the item, because adding the first item triggers changed event
+		b.visible = append(b.visible, i)
+		b.hosts.AddItem(b.hostItem(h), "", 0, nil)
+	}
+	b.hosts.SetTitle(fmt.Sprintf(" Hosts (%d/%d) ", len(b.visible), len(b.run.Host)))
+	if len(b.visible) == 0 {
+		b.showHost(-1)
+	}
+}
+
+// hostItem returns list item text of the host: marker, addresses, hostname and number of open ports
+func (b *browser) hostItem(h *formatter.Host) string {
+	marker := "  "
+	if b.marked[h.JoinedAddresses("/")] {
+		marker = "[yellow]*[-] "
+	}
+	open := 0
+	for i := range h.Port {
+		if h.Port[i].State.State == "open" {
+			open++
+		}
+	}
+	item := marker + tview.Escape(h.JoinedAddresses("/"))
+	if len(h.HostNames.HostName) > 0 {
+		item += " " + tview.Escape(h.HostNames.HostName[0].Name)
+	}
+	return item + fmt.Sprintf(" [gray](%d open)[-]", open)
+}
+
+// hostSearchText returns lowercase text that search query is matched against:
+// addresses, hostnames, OS names, ports and services
+func hostSearchText(h *formatter.Host) string {
+	parts := []string{h.JoinedAddresses(" "), h.JoinedHostNames(" "), h.Status.State}
+	for _, m := range h.OS.OSMatch {
+		parts = append(parts, m.Name)
+	}
+	for _, p := range h.Port {
+		parts = append(parts, fmt.Sprintf("%d/%s", p.PortID, p.Protocol), p.Service.Name, p.Service.Product, p.Service.Version)
+	}
+	return strings.ToLower(strings.Join(parts, " "))
+}
+
+// toggleMark marks the selected host for export or removes the mark
+func (b *browser) toggleMark() {
+	index := b.hosts.GetCurrentItem()
+	if index < 0 || index >= len(b.visible) {
+		return
+	}
+	h := &b.filtered.Host[b.visible[index]]
+	key := h.JoinedAddresses("/")
+	if b.marked[key] {
+		delete(b.marked, key)
+	} else {
+		b.marked[key] = true
+	}
+	b.hosts.SetItemText(index, b.hostItem(h), "")
+}
+
+// selection returns NMAPRun with the marked hosts, or with all hosts in the list if nothing is marked
+func (b *browser) selection() formatter.NMAPRun {
+	run := b.filtered
+	run.Host = []formatter.Host{}
+	if len(b.marked) == 0 {
+		for _, i := range b.visible {
+			run.Host = append(run.Host, b.filtered.Host[i])
+		}
+		return run
+	}
+	for i := range b.filtered.Host {
+		if b.marked[b.filtered.Host[i].JoinedAddresses("/")] {
+			run.Host = append(run.Host, b.filtered.Host[i])
+		}
+	}
+	return run
+}
+
+// exportSelection exports selected hosts, input contains output format and output file, e.g.: `html report.html`
+func (b *browser) exportSelection(input string) (err error) {
+	format, path, _ := strings.Cut(strings.TrimSpace(input), " ")
+	path = strings.TrimSpace(path)
+	if format == "" || path == "" {
+		return errors.New("output format and file are required, e.g.: html report.html")
+	}
+	c := b.config
+	c.OutputFormat = formatter.OutputFormat(format)
+	c.OutputFile = formatter.OutputFile(path)
+	if err = validate(c); err != nil {
+		return err
+	}
+	w := &formatter.MainWorkflow{}
+	w.SetConfig(&c)
+	w.SetOutputFile()
+	err = w.Format(b.selection())
+	if f, ok := c.Writer.(*formatter.AtomicFile); ok && err != nil {
+		_ = f.Abort()
+		return err
+	}
+	if closeErr := c.Writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// message displays the message in the status line, key bindings are displayed if the message is empty
+func (b *browser) message(text string, isError bool) {
+	switch {
+	case text == "":
+		b.status.SetText(browseHelp)
+	case isError:
+		b.status.SetText("[red]" + tview.Escape(text) + "[-]")
+	default:
+		b.status.SetText("[green]" + tview.Escape(text) + "[-]")
+	}
+}
+
+// showHost displays details of the host in the list, details are cleared if there is no such host
+func (b *browser) showHost(index int) {
+	b.details.Clear()
+	if index < 0 || index >= len(b.visible) {
+		b.details.SetTitle("")
+		return
+	}
+	h := &b.filtered.Host[b.visible[index]]
+	b.details.SetTitle(" " + tview.Escape(h.JoinedAddresses("/")) + " ")
+	b.details.SetText(hostDetails(h)).ScrollToBeginning()
+}
+
+// hostDetails returns host information, ports, scripts and traceroute formatted with tview color tags
+func hostDetails(h *formatter.Host) string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "[::b]Status:[::-]    %s (%s)\n", tview.Escape(h.Status.State), tview.Escape(h.Status.Reason))
+	for _, hostname := range h.HostNames.HostName {
+		fmt.Fprintf(&s, "[::b]Hostname:[::-]  %s (%s)\n", tview.Escape(hostname.Name), tview.Escape(hostname.Type))
+	}
+	for _, m := range h.OS.OSMatch {
+		fmt.Fprintf(&s, "[::b]OS:[::-]        %s (%s%%)\n", tview.Escape(m.Name), tview.Escape(m.Accuracy))
+	}
+	if h.Distance.Value > 0 {
+		fmt.Fprintf(&s, "[::b]Distance:[::-]  %d hops\n", h.Distance.Value)
+	}
+
+	s.WriteString("\n[::b]Ports[::-]\n")
+	if len(h.Port) == 0 {
+		s.WriteString("[gray]No ports[-]\n")
+	}
+	portWidth, stateWidth, serviceWidth := 0, 0, 0
+	for _, p := range h.Port {
+		portWidth = max(portWidth, len(fmt.Sprintf("%d/%s", p.PortID, p.Protocol)))
+		stateWidth = max(stateWidth, len(p.State.State))
+		serviceWidth = max(serviceWidth, len(p.Service.Name))
+	}
+	for _, p := range h.Port {
+		version := strings.TrimSpace(strings.Join([]string{p.Service.Product, p.Service.Version, p.Service.ExtraInfo}, " "))
+		fmt.Fprintf(
+			&s, "%-*s  [%s]%-*s[-]  %-*s  %s\n",
+			portWidth, fmt.Sprintf("%d/%s", p.PortID, p.Protocol),
+			browsePortStateColor(p.State.State), stateWidth, tview.Escape(p.State.State),
+			serviceWidth, tview.Escape(p.Service.Name),
+			tview.Escape(version),
+		)
+		for _, script := range p.Script {
+			fmt.Fprintf(&s, "[gray]| %s:[-]\n", tview.Escape(script.ID))
+			for _, line := range strings.Split(strings.Trim(script.Output, "\n"), "\n") {
+				fmt.Fprintf(&s, "[gray]|[-]   %s\n", tview.Escape(line))
+			}
+		}
+	}
+
+	if len(h.Trace.Hops) > 0 {
+		s.WriteString("\n[::b]Traceroute[::-]\n")
+		for _, hop := range h.Trace.Hops {
+			fmt.Fprintf(&s, "%3d  %8.2f ms  %s %s\n", hop.TTL, hop.RTT, tview.Escape(hop.IPAddr), tview.Escape(hop.Host))
+		}
+	}
+	return s.String()
+}
+
+// browsePortStateColor returns the same port state colors as the ones that are used in graphs
+func browsePortStateColor(state string) string {
+	switch state {
+	case "open":
+		return formatter.DotOpenPortColor
+	case "filtered":
+		return formatter.DotFilteredPortColor
+	case "closed":
+		return formatter.DotClosedPortColor
+	}
+	return formatter.DotDefaultColor
+}
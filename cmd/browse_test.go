@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/vdjagilev/nmap-formatter/v3/formatter"
+)
+
+// browseTestRun contains only the fields browser tests search, filter and export by
+func browseTestRun() formatter.NMAPRun {
+	return formatter.NMAPRun{
+		Host: []formatter.Host{
+			{
+				HostAddress: []formatter.HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}},
+				HostNames:   formatter.HostNames{HostName: []formatter.HostName{{Name: "web.local"}}},
+				Status:      formatter.HostStatus{State: "up"},
+				Port: []formatter.Port{
+					{Protocol: "tcp", PortID: 80, State: formatter.PortState{State: "open"}, Service: formatter.PortService{Name: "http", Product: "nginx"}},
+				},
+			},
+			{
+				HostAddress: []formatter.HostAddress{{Address: "10.0.0.2", AddressType: "ipv4"}},
+				Status:      formatter.HostStatus{State: "up"},
+				Port: []formatter.Port{
+					{Protocol: "tcp", PortID: 22, State: formatter.PortState{State: "open"}, Service: formatter.PortService{Name: "ssh", Product: "OpenSSH"}},
+				},
+			},
+			{
+				HostAddress: []formatter.HostAddress{{Address: "10.0.0.3", AddressType: "ipv4"}},
+				Status:      formatter.HostStatus{State: "down"},
+			},
+		},
+	}
+}
+
+func Test_newBrowser(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    formatter.Config
+		wantHosts int
+		wantErr   bool
+	}{
+		{name: "All hosts", config: formatter.Config{}, wantHosts: 3},
+		{name: "Skip down hosts", config: formatter.Config{SkipDownHosts: true}, wantHosts: 2},
+		{
+			name:      "Filter expressions",
+			config:    formatter.Config{FilterExpressions: []string{`.Status.State == "up"`, `any(.Port, { .PortID == 22 })`}},
+			wantHosts: 1,
+		},
+		{name: "Wrong filter expression", config: formatter.Config{FilterExpressions: []string{"wrong("}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newBrowser(browseTestRun(), tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newBrowser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := b.hosts.GetItemCount(); got != tt.wantHosts {
+				t.Errorf("newBrowser() hosts = %d, want %d", got, tt.wantHosts)
+			}
+		})
+	}
+}
+
+func Test_browser_search(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{name: "Empty query", query: "", want: []int{0, 1, 2}},
+		{name: "Hostname", query: "WEB", want: []int{0}},
+		{name: "Service product", query: "openssh", want: []int{1}},
+		{name: "Port", query: "80/tcp", want: []int{0}},
+		{name: "Nothing found", query: "mysql", want: []int{}},
+	}
+	b, err := newBrowser(browseTestRun(), formatter.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b.search.SetText(tt.query)
+			if fmt.Sprint(b.visible) != fmt.Sprint(tt.want) {
+				t.Errorf("browser.search visible = %v, want %v", b.visible, tt.want)
+			}
+			if b.hosts.GetItemCount() != len(tt.want) {
+				t.Errorf("browser.search list has %d items, want %d", b.hosts.GetItemCount(), len(tt.want))
+			}
+		})
+	}
+}
+
+func Test_browser_applyFilter(t *testing.T) {
+	b, err := newBrowser(browseTestRun(), formatter.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.applyFilter(`any(.Port, { .Service.Name == "http" })`); err != nil {
+		t.Fatalf("browser.applyFilter() error = %v", err)
+	}
+	if len(b.filtered.Host) != 1 {
+		t.Errorf("browser.applyFilter() hosts = %d, want 1", len(b.filtered.Host))
+	}
+	// Previous results are kept while expression is being edited
+	if err = b.applyFilter(`any(.Port, {`); err == nil {
+		t.Errorf("browser.applyFilter() expected error")
+	}
+	if len(b.filtered.Host) != 1 {
+		t.Errorf("browser.applyFilter() hosts = %d after wrong expression, want 1", len(b.filtered.Host))
+	}
+	if err = b.applyFilter(" "); err != nil || len(b.filtered.Host) != 3 {
+		t.Errorf("browser.applyFilter() empty expression error = %v, hosts = %d", err, len(b.filtered.Host))
+	}
+}
+
+func Test_browser_handleKey(t *testing.T) {
+	b, err := newBrowser(browseTestRun(), formatter.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.handleKey(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)) != nil || b.app.GetFocus() != b.details {
+		t.Errorf("browser.handleKey() tab should focus details")
+	}
+	b.handleKey(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	b.handleKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	if !b.marked["10.0.0.1"] {
+		t.Errorf("browser.handleKey() space should mark the host, marked = %v", b.marked)
+	}
+	if main, _ := b.hosts.GetItemText(0); !strings.Contains(main, "*") {
+		t.Errorf("browser.handleKey() marked host should have a marker, item = %q", main)
+	}
+	b.handleKey(tcell.NewEventKey(tcell.KeyRune, '/', tcell.ModNone))
+	if b.app.GetFocus() != b.search {
+		t.Errorf("browser.handleKey() / should focus search")
+	}
+	// Keys are passed to the focused input field
+	if b.handleKey(tcell.NewEventKey(tcell.KeyRune, 'q', tcell.ModNone)) == nil {
+		t.Errorf("browser.handleKey() should not handle keys while search is focused")
+	}
+}
+
+func Test_browser_exportSelection(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.json")
+	if err := os.WriteFile(existing, []byte{}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		input     string
+		marked    []string
+		wantHosts []string
+		wantErr   bool
+	}{
+		{name: "All hosts", input: "json " + filepath.Join(dir, "all.json"), wantHosts: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}},
+		{name: "Marked hosts", input: "json " + filepath.Join(dir, "marked.json"), marked: []string{"10.0.0.2"}, wantHosts: []string{"10.0.0.2"}},
+		{name: "Missing output file", input: "json", wantErr: true},
+		{name: "Wrong output format", input: "doc " + filepath.Join(dir, "report.doc"), wantErr: true},
+		{name: "Output file exists", input: "json " + existing, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := newBrowser(browseTestRun(), formatter.Config{InputFileConfig: formatter.InputFileConfig{IsStdin: true}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range tt.marked {
+				b.marked[m] = true
+			}
+			err = b.exportSelection(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("browser.exportSelection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			content, err := os.ReadFile(strings.Fields(tt.input)[1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			var run formatter.NMAPRun
+			if err = json.Unmarshal(content, &run); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for i := range run.Host {
+				got = append(got, run.Host[i].JoinedAddresses("/"))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantHosts, ",") {
+				t.Errorf("browser.exportSelection() hosts = %v, want %v", got, tt.wantHosts)
+			}
+		})
+	}
+}
+
+func Test_joinExpressions(t *testing.T) {
+	tests := []struct {
+		name        string
+		expressions []string
+		want        string
+	}{
+		{name: "No expressions", expressions: []string{}, want: ""},
+		{name: "Single expression", expressions: []string{"a == 1"}, want: "a == 1"},
+		{name: "Multiple expressions", expressions: []string{"a == 1", "b || c"}, want: "(a == 1) && (b || c)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinExpressions(tt.expressions); got != tt.want {
+				t.Errorf("joinExpressions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"bytes"
 	_ "embed"
 	"errors"
 	"os"
@@ -88,6 +89,37 @@ func Test_validate(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Wrong CSV delimiter",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.CSVOutput,
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_csv"),
+					},
+					OutputOptions: formatter.OutputOptions{
+						CSVOptions: formatter.CSVOutputOptions{
+							Delimiter: ";;",
+						},
+					},
+				},
+			},
+			wantErr: true,
+			before: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_csv")
+				_, err := os.Create(path)
+				if err != nil {
+					t.Errorf("could not create temporary file: %s", path)
+				}
+			},
+			after: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_csv")
+				err := os.Remove(path)
+				if err != nil {
+					t.Logf("could not remove temporary file: %s", path)
+				}
+			},
+		},
 		{
 			name: "Successful validation output file",
 			args: args{
@@ -109,6 +141,155 @@ func Test_validate(t *testing.T) {
 			},
 			after: func(t *testing.T) {},
 		},
+		{
+			name: "Existing output file is replaced atomically",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.PrometheusOutput,
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_prometheus"),
+					},
+					OutputFile: formatter.OutputFile(path.Join(os.TempDir(), "formatter_cmd_valid_prometheus.prom")),
+				},
+			},
+			wantErr: false,
+			before: func(t *testing.T) {
+				for _, p := range []string{"formatter_cmd_valid_prometheus", "formatter_cmd_valid_prometheus.prom"} {
+					_, err := os.Create(path.Join(os.TempDir(), p))
+					if err != nil {
+						t.Errorf("could not create temporary file: %s", p)
+					}
+				}
+			},
+			after: func(t *testing.T) {
+				for _, p := range []string{"formatter_cmd_valid_prometheus", "formatter_cmd_valid_prometheus.prom"} {
+					err := os.Remove(path.Join(os.TempDir(), p))
+					if err != nil {
+						t.Logf("could not remove temporary file: %s", p)
+					}
+				}
+			},
+		},
+		{
+			name: "Output file with ECS url",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.ECSOutput,
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_ecs"),
+					},
+					OutputFile: formatter.OutputFile(path.Join(os.TempDir(), "formatter_cmd_valid_ecs.ndjson")),
+					OutputOptions: formatter.OutputOptions{
+						ECSOptions: formatter.ECSOutputOptions{URL: "http://localhost:9200/_bulk"},
+					},
+				},
+			},
+			wantErr: true,
+			before: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_ecs")
+				_, err := os.Create(path)
+				if err != nil {
+					t.Errorf("could not create temporary file: %s", path)
+				}
+			},
+			after: func(t *testing.T) {
+				_ = os.Remove(path.Join(os.TempDir(), "formatter_cmd_valid_ecs"))
+				output := path.Join(os.TempDir(), "formatter_cmd_valid_ecs.ndjson")
+				if _, err := os.Stat(output); err == nil {
+					t.Errorf("output file should not be created: %s", output)
+					_ = os.Remove(output)
+				}
+			},
+		},
+		{
+			name: "Output file with Parquet directory",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.ParquetOutput,
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_parquet"),
+					},
+					OutputFile: formatter.OutputFile(path.Join(os.TempDir(), "formatter_cmd_valid_parquet.parquet")),
+					OutputOptions: formatter.OutputOptions{
+						ParquetOptions: formatter.ParquetOutputOptions{Directory: os.TempDir()},
+					},
+				},
+			},
+			wantErr: true,
+			before: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_parquet")
+				_, err := os.Create(path)
+				if err != nil {
+					t.Errorf("could not create temporary file: %s", path)
+				}
+			},
+			after: func(t *testing.T) {
+				_ = os.Remove(path.Join(os.TempDir(), "formatter_cmd_valid_parquet"))
+				output := path.Join(os.TempDir(), "formatter_cmd_valid_parquet.parquet")
+				if _, err := os.Stat(output); err == nil {
+					t.Errorf("output file should not be created: %s", output)
+					_ = os.Remove(output)
+				}
+			},
+		},
+		{
+			name: "Zero subnet prefix",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.MermaidOutput,
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_subnet"),
+					},
+					OutputOptions: formatter.OutputOptions{
+						MermaidOptions: formatter.MermaidOutputOptions{SubnetPrefix: 0},
+					},
+				},
+			},
+			wantErr: true,
+			before: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_subnet")
+				_, err := os.Create(path)
+				if err != nil {
+					t.Errorf("could not create temporary file: %s", path)
+				}
+			},
+			after: func(t *testing.T) {
+				path := path.Join(os.TempDir(), "formatter_cmd_valid_subnet")
+				err := os.Remove(path)
+				if err != nil {
+					t.Logf("could not remove temporary file: %s", path)
+				}
+			},
+		},
+		{
+			name: "Template with the format that does not use it",
+			args: args{
+				config: formatter.Config{
+					OutputFormat: formatter.TableOutput,
+					TemplatePath: path.Join(os.TempDir(), "formatter_template_table"),
+					InputFileConfig: formatter.InputFileConfig{
+						Path: path.Join(os.TempDir(), "formatter_cmd_valid_template_table"),
+					},
+				},
+			},
+			wantErr: true,
+			before: func(t *testing.T) {
+				for _, p := range []string{"formatter_template_table", "formatter_cmd_valid_template_table"} {
+					_, err := os.Create(path.Join(os.TempDir(), p))
+					if err != nil {
+						t.Errorf("could not create temporary file: %s", p)
+					}
+				}
+			},
+			after: func(t *testing.T) {
+				for _, p := range []string{"formatter_template_table", "formatter_cmd_valid_template_table"} {
+					err := os.Remove(path.Join(os.TempDir(), p))
+					if err != nil {
+						t.Logf("could not remove temporary file: %s", p)
+					}
+				}
+			},
+		},
 		{
 			name: "Successful validation template",
 			args: args{
@@ -164,9 +345,10 @@ func Test_arguments(t *testing.T) {
 		args []string
 	}
 	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
+		name      string
+		args      args
+		templates map[formatter.OutputFormat]string
+		wantErr   bool
 	}{
 		{
 			name:    "No XML path argument provided",
@@ -197,12 +379,40 @@ func Test_arguments(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Template of the output format",
+			args: args{
+				args: []string{"latex"},
+			},
+			templates: map[formatter.OutputFormat]string{formatter.LaTeXOutput: "report.tex"},
+			wantErr:   false,
+		},
+		{
+			name: "Template of another output format",
+			args: args{
+				args: []string{"md"},
+			},
+			templates: map[formatter.OutputFormat]string{formatter.HTMLOutput: "report.html"},
+			wantErr:   true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			for format, path := range tt.templates {
+				*templatePaths[format] = path
+			}
+			defer func() {
+				for format := range tt.templates {
+					*templatePaths[format] = ""
+				}
+				config.TemplatePath = ""
+			}()
 			if err := arguments(tt.args.cmd, tt.args.args); (err != nil) != tt.wantErr {
 				t.Errorf("arguments() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if path := tt.templates[config.OutputFormat]; !tt.wantErr && config.TemplatePath != path {
+				t.Errorf("arguments() template path = %s, want %s", config.TemplatePath, path)
+			}
 		})
 	}
 }
@@ -293,6 +503,18 @@ func Test_run(t *testing.T) {
 			args:    args{},
 			wantErr: false,
 		},
+		{
+			name:      "Output could not be written on close",
+			input:     path.Join(os.TempDir(), "formatter_cmd_run_3"),
+			runBefore: true,
+			workflow:  &testWorkflow{},
+			config: formatter.Config{
+				OutputFormat: "html",
+				Writer:       &rootMockedWriter{err: errors.New("rename failed")},
+			},
+			args:    args{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -307,6 +529,35 @@ func Test_run(t *testing.T) {
 	}
 }
 
+func Test_run_removesAtomicFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	input := path.Join(dir, "nmap.xml")
+	if err := os.WriteFile(input, []byte{}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	output, err := formatter.NewAtomicFile(path.Join(dir, "nmap.prom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	workflow = &testWorkflow{executeResult: errors.New("Bad failure")}
+	config = formatter.Config{
+		OutputFormat:    "prometheus",
+		Writer:          output,
+		InputFileConfig: formatter.InputFileConfig{Path: input},
+	}
+	defer func() {
+		workflow = nil
+		config = formatter.Config{}
+	}()
+	if err = run(nil, nil); err == nil {
+		t.Fatalf("run() expected error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() != "nmap.xml" {
+		t.Errorf("run() should remove temporary output file, files: %v", entries)
+	}
+}
+
 type testWorkflow struct {
 	executeResult error
 }
@@ -326,6 +577,7 @@ func (w *testWorkflow) SetOutputFile() {
 
 type rootMockedWriter struct {
 	data []byte
+	err  error
 }
 
 func (w *rootMockedWriter) Write(p []byte) (n int, err error) {
@@ -334,7 +586,7 @@ func (w *rootMockedWriter) Write(p []byte) (n int, err error) {
 }
 
 func (w *rootMockedWriter) Close() error {
-	return nil
+	return w.err
 }
 
 func Test_shouldShowVersion(t *testing.T) {
@@ -416,3 +668,39 @@ func Test_shouldShowVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_schema(t *testing.T) {
+	tests := []struct {
+		name      string
+		snakeCase bool
+		want      string
+	}{
+		{
+			name:      "CamelCase schema",
+			snakeCase: false,
+			want:      formatter.JSONSchema,
+		},
+		{
+			name:      "snake_case schema",
+			snakeCase: true,
+			want:      formatter.JSONSchemaSnakeCase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaSnakeCase = tt.snakeCase
+			defer func() {
+				schemaSnakeCase = false
+			}()
+			out := new(bytes.Buffer)
+			cmd := &cobra.Command{}
+			cmd.SetOut(out)
+			if err := schema(cmd, []string{}); err != nil {
+				t.Errorf("schema() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("schema() output does not match embedded schema")
+			}
+		})
+	}
+}
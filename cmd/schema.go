@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vdjagilev/nmap-formatter/v3/formatter"
+)
+
+// schemaSnakeCase defines whether snake_case variant of JSON schema should be printed
+var schemaSnakeCase bool
+
+// schemaCmd prints JSON schema of the JSON output
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints JSON schema of the JSON output",
+	Long:  `Prints versioned JSON schema (draft 2020-12) that describes JSON output of nmap-formatter`,
+	Args:  cobra.NoArgs,
+	RunE:  schema,
+}
+
+func init() {
+	schemaCmd.Flags().BoolVar(&schemaSnakeCase, "json-snake-case", false, "--json-snake-case=true (prints schema with snake_case keys)")
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// schema prints embedded JSON schema
+func schema(cmd *cobra.Command, args []string) error {
+	content := formatter.JSONSchema
+	if schemaSnakeCase {
+		content = formatter.JSONSchemaSnakeCase
+	}
+	_, err := fmt.Fprint(cmd.OutOrStdout(), content)
+	return err
+}
@@ -10,7 +10,7 @@ import (
 // validate is checking input from the command line
 func validate(config formatter.Config) error {
 	if !config.OutputFormat.IsValid() {
-		return fmt.Errorf("not valid format: %s, please choose html/json/md/csv/excel/sqlite/d2", config.OutputFormat)
+		return fmt.Errorf("not valid format: %s, please choose html/json/jsonl/yaml/xml/pdf/md/csv/excel/sqlite/dot/mermaid/graphml/gexf/cyclonedx/stix/ecs/prometheus/ansible/parquet/sql/cypher/latex/adoc/table/d2", config.OutputFormat)
 	}
 
 	err := validateIOFiles(config)
@@ -18,7 +18,65 @@ func validate(config formatter.Config) error {
 		return err
 	}
 
-	return validateTemplateConfig(config)
+	err = validateTemplateConfig(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateCSVOptions(config)
+	if err != nil {
+		return err
+	}
+
+	err = validateSubnetPrefix(config)
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.JSONLinesOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.DotOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.MermaidOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.ECSOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.PrometheusOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.AnsibleOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.ParquetOptions.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = config.OutputOptions.SQLOptions.Validate()
+	if err != nil {
+		return err
+	}
+	err = config.OutputOptions.TableOptions.Validate()
+	if err != nil {
+		return err
+	}
+	return config.OutputOptions.D2LangOptions.Validate()
 }
 
 // validateIOFiles validates whether Input files and output files exists/have permissions to be created
@@ -30,9 +88,25 @@ func validateIOFiles(config formatter.Config) error {
 			return fmt.Errorf("could not open XML file: %v", err)
 		}
 	}
+	// Documents are sent to the endpoint, output file would be left empty
+	if config.OutputFile != "" && config.OutputFormat == formatter.ECSOutput && config.OutputOptions.ECSOptions.URL != "" {
+		return fmt.Errorf("output file cannot be used together with ecs url")
+	}
+	// Tables are written to the directory, output file would be left empty
+	if config.OutputFile != "" && config.OutputFormat == formatter.ParquetOutput && config.OutputOptions.ParquetOptions.Directory != "" {
+		return fmt.Errorf("output file cannot be used together with parquet directory")
+	}
 	// Checking if output file can be created and does not exist already
 	// If OutputFile == "", it means that all output goes to stdout, no check needed
-	if config.OutputFile != "" {
+	if config.OutputFile != "" && config.OutputFormat.AtomicOutput() {
+		// Output file is replaced, only temporary file in the same directory has to be created
+		outputFile, err := formatter.NewAtomicFile(string(config.OutputFile))
+		if err != nil {
+			return fmt.Errorf("unable to create output file: %s", err)
+		}
+		_ = outputFile.File.Close()
+		_ = os.Remove(outputFile.Name())
+	} else if config.OutputFile != "" {
 		outputFile, err := os.OpenFile(string(config.OutputFile), os.O_EXCL|os.O_WRONLY|os.O_CREATE, os.ModePerm)
 		if err != nil {
 			return fmt.Errorf("unable to create output file: %s", err)
@@ -47,10 +121,8 @@ func validateIOFiles(config formatter.Config) error {
 func validateTemplateConfig(config formatter.Config) error {
 	// Checking if custom template is existing and readable and
 	if config.TemplatePath != "" {
-		switch config.OutputFormat {
-		case formatter.CSVOutput:
-		case formatter.JSONOutput:
-			return fmt.Errorf("cannot set templates for the formats other than HTML or Markdown")
+		if !config.OutputFormat.UsesTemplate() {
+			return fmt.Errorf("cannot set templates for the formats other than HTML, Markdown, LaTeX or AsciiDoc")
 		}
 		file, err := os.Open(config.TemplatePath)
 		if err != nil {
@@ -62,3 +134,30 @@ func validateTemplateConfig(config formatter.Config) error {
 	}
 	return nil
 }
+
+// validateSubnetPrefix rejects zero subnet prefix of the chosen output format, formatters
+// treat zero prefix as not set and use formatter.DefaultIPv4SubnetPrefix instead
+func validateSubnetPrefix(config formatter.Config) error {
+	prefixes := map[formatter.OutputFormat]int{
+		formatter.DotOutput:     config.OutputOptions.DotOptions.SubnetPrefix,
+		formatter.MermaidOutput: config.OutputOptions.MermaidOptions.SubnetPrefix,
+		formatter.AnsibleOutput: config.OutputOptions.AnsibleOptions.SubnetPrefix,
+		formatter.D2LangOutput:  config.OutputOptions.D2LangOptions.SubnetPrefix,
+	}
+	if prefix, ok := prefixes[config.OutputFormat]; ok && prefix == 0 {
+		return fmt.Errorf("subnet prefix should be between 1 and 32: %d", prefix)
+	}
+	return nil
+}
+
+// validateCSVOptions validates CSV delimiter and chosen columns
+func validateCSVOptions(config formatter.Config) error {
+	if config.OutputFormat != formatter.CSVOutput {
+		return nil
+	}
+	_, err := config.OutputOptions.CSVOptions.Comma()
+	if err != nil {
+		return err
+	}
+	return config.OutputOptions.CSVOptions.ValidateColumns()
+}
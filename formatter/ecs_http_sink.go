@@ -0,0 +1,101 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ecsRetryDelay is a delay before the first retry of failed bulk request, every next retry waits longer
+var ecsRetryDelay = time.Second
+
+// ECSHTTPSink sends ECS documents to `_bulk` API endpoint in batches
+type ECSHTTPSink struct {
+	client    *http.Client
+	url       string
+	batchSize int
+	retries   int
+}
+
+// ecsBulkResponse is a part of `_bulk` API response that shows whether any item has failed
+type ecsBulkResponse struct {
+	Errors bool `json:"errors"`
+}
+
+// newECSHTTPSink returns new HTTP sink, credentials can be passed in URL (basic auth)
+func newECSHTTPSink(o *ECSOutputOptions) *ECSHTTPSink {
+	batchSize := o.BatchSize
+	if batchSize == 0 {
+		batchSize = ECSDefaultBatchSize
+	}
+	return &ECSHTTPSink{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		url:       o.URL,
+		batchSize: batchSize,
+		retries:   o.Retries,
+	}
+}
+
+// Send posts bulk lines (action & document pairs) in batches of `batchSize` documents
+func (s *ECSHTTPSink) Send(lines [][]byte) error {
+	batchLines := s.batchSize * 2
+	for start := 0; start < len(lines); start += batchLines {
+		end := start + batchLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if err := s.post(bytes.Join(lines[start:end], nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// post sends one batch, request is retried on network errors, 429 and 5xx responses
+func (s *ECSHTTPSink) post(body []byte) (err error) {
+	for attempt := 0; attempt <= s.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(ecsRetryDelay * time.Duration(attempt))
+		}
+		var retry bool
+		retry, err = s.do(body)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return fmt.Errorf("ecs bulk request failed after %d retries: %v", s.retries, err)
+}
+
+// do sends the request and returns whether it makes sense to retry it in case of error
+func (s *ECSHTTPSink) do(body []byte) (bool, error) {
+	request, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	response, err := s.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return true, err
+	}
+	if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+		return true, fmt.Errorf("ecs bulk request returned status %d", response.StatusCode)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return false, fmt.Errorf("ecs bulk request returned status %d: %s", response.StatusCode, content)
+	}
+	var result ecsBulkResponse
+	if json.Unmarshal(content, &result) == nil && result.Errors {
+		return false, fmt.Errorf("ecs bulk request has failed items: %s", content)
+	}
+	return false, nil
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/expr-lang/expr"
 )
 
+// SkipDownHostsExpression is a filter expression which is applied to skip hosts that are down
+const SkipDownHostsExpression = ".Status.State == 'up'"
+
 // filterExpr filters NMAPRun.Hosts by given expression
 func filterExpr(r NMAPRun, code string) (NMAPRun, error) {
 	program, err := expr.Compile(
@@ -32,6 +35,19 @@ func filterExpr(r NMAPRun, code string) (NMAPRun, error) {
 	return r, nil
 }
 
+// FilterNMAPRun returns NMAPRun with the hosts that match all the expressions,
+// expressions have the same syntax as the ones passed with `--filter` flag
+func FilterNMAPRun(r NMAPRun, expressions []string) (NMAPRun, error) {
+	var err error
+	for _, code := range expressions {
+		r, err = filterExpr(r, code)
+		if err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
 // convertToHosts converts output from expression engine to []Host
 func convertToHosts(output interface{}) ([]Host, error) {
 	outputInterfaces, ok := output.([]interface{})
@@ -3,6 +3,7 @@ package formatter
 import (
 	"io"
 	"os"
+	"path/filepath"
 )
 
 // OutputFile describes output file name (full path)
@@ -26,3 +27,47 @@ func (i *InputFileConfig) ExistsOpen() error {
 	}
 	return f.Close()
 }
+
+// AtomicFile is an output file that is written to a temporary file in the same directory first,
+// the temporary file replaces the destination on Close(), so readers never see partially written content
+type AtomicFile struct {
+	*os.File
+	path string
+}
+
+// NewAtomicFile creates a hidden temporary file next to the destination file
+func NewAtomicFile(path string) (*AtomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &AtomicFile{File: f, path: path}, nil
+}
+
+// Close flushes the content to the disk and renames temporary file to the destination
+func (a *AtomicFile) Close() error {
+	err := a.File.Sync()
+	if err == nil {
+		err = a.File.Close()
+	} else {
+		_ = a.File.Close()
+	}
+	if err == nil {
+		// Temporary files are created with 0600 permissions, destination should be readable by others
+		err = os.Chmod(a.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(a.Name(), a.path)
+	}
+	if err != nil {
+		_ = os.Remove(a.Name())
+	}
+	return err
+}
+
+// Abort closes and removes temporary file without replacing the destination,
+// it's used when output could not be written completely
+func (a *AtomicFile) Abort() error {
+	_ = a.File.Close()
+	return os.Remove(a.Name())
+}
@@ -71,3 +71,59 @@ func TestInputFileConfig_ExistsOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestAtomicFile_Close(t *testing.T) {
+	dir := t.TempDir()
+	destination := path.Join(dir, "nmap.prom")
+	if err := os.WriteFile(destination, []byte("old"), 0o644); err != nil {
+		t.Fatalf("could not create destination file: %v", err)
+	}
+
+	f, err := NewAtomicFile(destination)
+	if err != nil {
+		t.Fatalf("NewAtomicFile() error = %v", err)
+	}
+	if _, err = f.Write([]byte("new")); err != nil {
+		t.Fatalf("AtomicFile.Write() error = %v", err)
+	}
+	// Destination is not changed until the file is closed
+	if content, _ := os.ReadFile(destination); string(content) != "old" {
+		t.Errorf("destination content before Close() = %s, want old", content)
+	}
+	if err = f.Close(); err != nil {
+		t.Fatalf("AtomicFile.Close() error = %v", err)
+	}
+	if content, _ := os.ReadFile(destination); string(content) != "new" {
+		t.Errorf("destination content after Close() = %s, want new", content)
+	}
+	if info, _ := os.Stat(destination); info.Mode().Perm() != 0o644 {
+		t.Errorf("destination permissions = %v, want 0644", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary file is left in the directory: %v", entries)
+	}
+}
+
+func TestAtomicFile_Abort(t *testing.T) {
+	dir := t.TempDir()
+	destination := path.Join(dir, "nmap.prom")
+	f, err := NewAtomicFile(destination)
+	if err != nil {
+		t.Fatalf("NewAtomicFile() error = %v", err)
+	}
+	if _, err = f.Write([]byte("partial")); err != nil {
+		t.Fatalf("AtomicFile.Write() error = %v", err)
+	}
+	if err = f.Abort(); err != nil {
+		t.Fatalf("AtomicFile.Abort() error = %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("AtomicFile.Abort() should not leave any files: %v", entries)
+	}
+}
+
+func TestNewAtomicFile(t *testing.T) {
+	if _, err := NewAtomicFile(path.Join(t.TempDir(), "missing", "nmap.prom")); err == nil {
+		t.Errorf("NewAtomicFile() expected error when directory does not exist")
+	}
+}
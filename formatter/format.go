@@ -12,6 +12,42 @@ const (
 	MarkdownOutput OutputFormat = "md"
 	// JSONOutput constant defines OutputFormat for JavaScript Object Notation, which is more useful for machine-related operations (parsing)
 	JSONOutput OutputFormat = "json"
+	// JSONLinesOutput constant defines OutputFormat for JSON Lines, where every line is a separate host or port JSON record
+	JSONLinesOutput OutputFormat = "jsonl"
+	// YAMLOutput constant defines OutputFormat for YAML, which contains the same data as JSON but is easier to read and diff
+	YAMLOutput OutputFormat = "yaml"
+	// XMLOutput constant defines OutputFormat for nmap XML, which is useful to pass filtered results to other tools
+	XMLOutput OutputFormat = "xml"
+	// PDFOutput constant defines OutputFormat for PDF document, which is handy to share the report with others
+	PDFOutput OutputFormat = "pdf"
+	// MermaidOutput constant defines OutputFormat for Mermaid diagram, which is rendered natively by GitHub and many wikis
+	MermaidOutput OutputFormat = "mermaid"
+	// GraphMLOutput constant defines OutputFormat for GraphML, which can be opened in network analysis tools (Gephi, yEd, NetworkX)
+	GraphMLOutput OutputFormat = "graphml"
+	// GEXFOutput constant defines OutputFormat for GEXF (Graph Exchange XML Format), which is a native format of Gephi
+	GEXFOutput OutputFormat = "gexf"
+	// CycloneDXOutput constant defines OutputFormat for CycloneDX JSON, which lists discovered services and operating systems as software components
+	CycloneDXOutput OutputFormat = "cyclonedx"
+	// STIXOutput constant defines OutputFormat for STIX 2.1 bundle, which can be ingested by threat intelligence platforms
+	STIXOutput OutputFormat = "stix"
+	// ECSOutput constant defines OutputFormat for Elastic Common Schema NDJSON, which is ready to be sent to Elasticsearch/OpenSearch bulk API
+	ECSOutput OutputFormat = "ecs"
+	// PrometheusOutput constant defines OutputFormat for Prometheus text exposition format, which can be collected by node_exporter textfile collector
+	PrometheusOutput OutputFormat = "prometheus"
+	// AnsibleOutput constant defines OutputFormat for Ansible inventory (INI or YAML), where hosts are grouped by open services, OS family and subnet
+	AnsibleOutput OutputFormat = "ansible"
+	// ParquetOutput constant defines OutputFormat for Apache Parquet, which is a columnar format that can be queried with DuckDB, Spark or pandas
+	ParquetOutput OutputFormat = "parquet"
+	// SQLOutput constant defines OutputFormat for SQL dump (DDL and INSERT statements) for PostgreSQL, MySQL or SQLite, which can be loaded without a live database connection at convert time
+	SQLOutput OutputFormat = "sql"
+	// CypherOutput constant defines OutputFormat for Neo4j Cypher, which contains idempotent MERGE statements of hosts, ports, services, operating systems and route hops
+	CypherOutput OutputFormat = "cypher"
+	// LaTeXOutput constant defines OutputFormat for LaTeX document, which can be typeset with pdflatex
+	LaTeXOutput OutputFormat = "latex"
+	// AsciiDocOutput constant defines OutputFormat for AsciiDoc document, which can be used in Antora or rendered with Asciidoctor
+	AsciiDocOutput OutputFormat = "adoc"
+	// TableOutput constant defines OutputFormat for colored terminal table output
+	TableOutput OutputFormat = "table"
 	// DotOutput constant defined OutputFormat for Dot (Graphviz), which can be used to generate various graphs
 	DotOutput OutputFormat = "dot"
 	// SqliteOutput constant defines OutputFormat for sqlite file, which can be used to generate sqlite embedded databases
@@ -26,8 +62,23 @@ const (
 func (of OutputFormat) IsValid() bool {
 	// markdown & md is essentially the same thing
 	switch of {
-	case "markdown", "md", "html", "csv", "json", "dot", "sqlite", "excel", "d2":
+	case "markdown", "md", "html", "csv", "json", "dot", "sqlite", "excel", "d2", "jsonl", "yaml", "xml", "pdf", "mermaid", "graphml", "gexf", "cyclonedx", "stix", "ecs", "prometheus", "ansible", "parquet", "sql", "cypher", "latex", "adoc", "table":
 		return true
 	}
 	return false
 }
+
+// UsesTemplate returns whether output is rendered from the template, so custom template can be used
+func (of OutputFormat) UsesTemplate() bool {
+	switch of {
+	case HTMLOutput, MarkdownOutput, LaTeXOutput, AsciiDocOutput:
+		return true
+	}
+	return false
+}
+
+// AtomicOutput returns whether output file is replaced atomically, existing output file
+// is overwritten in this case (Prometheus textfile collector reads the same file periodically)
+func (of OutputFormat) AtomicOutput() bool {
+	return of == PrometheusOutput
+}
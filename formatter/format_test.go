@@ -50,6 +50,96 @@ func TestOutputFormat_IsValid(t *testing.T) {
 			of:   "sqlite",
 			want: true,
 		},
+		{
+			name: "jsonl",
+			of:   "jsonl",
+			want: true,
+		},
+		{
+			name: "yaml",
+			of:   "yaml",
+			want: true,
+		},
+		{
+			name: "xml",
+			of:   "xml",
+			want: true,
+		},
+		{
+			name: "pdf",
+			of:   "pdf",
+			want: true,
+		},
+		{
+			name: "mermaid",
+			of:   "mermaid",
+			want: true,
+		},
+		{
+			name: "graphml",
+			of:   "graphml",
+			want: true,
+		},
+		{
+			name: "gexf",
+			of:   "gexf",
+			want: true,
+		},
+		{
+			name: "cyclonedx",
+			of:   "cyclonedx",
+			want: true,
+		},
+		{
+			name: "stix",
+			of:   "stix",
+			want: true,
+		},
+		{
+			name: "ecs",
+			of:   "ecs",
+			want: true,
+		},
+		{
+			name: "prometheus",
+			of:   "prometheus",
+			want: true,
+		},
+		{
+			name: "ansible",
+			of:   "ansible",
+			want: true,
+		},
+		{
+			name: "parquet",
+			of:   "parquet",
+			want: true,
+		},
+		{
+			name: "sql",
+			of:   "sql",
+			want: true,
+		},
+		{
+			name: "cypher",
+			of:   "cypher",
+			want: true,
+		},
+		{
+			name: "latex",
+			of:   "latex",
+			want: true,
+		},
+		{
+			name: "adoc",
+			of:   "adoc",
+			want: true,
+		},
+		{
+			name: "table",
+			of:   "table",
+			want: true,
+		},
 		{
 			name: "excel",
 			of:   "sqlite",
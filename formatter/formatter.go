@@ -41,6 +41,78 @@ func New(config *Config) Formatter {
 		return &D2LangFormatter{
 			config,
 		}
+	case JSONLinesOutput:
+		return &JSONLinesFormatter{
+			config,
+		}
+	case YAMLOutput:
+		return &YAMLFormatter{
+			config,
+		}
+	case XMLOutput:
+		return &XMLFormatter{
+			config,
+		}
+	case PDFOutput:
+		return &PDFFormatter{
+			config,
+		}
+	case MermaidOutput:
+		return &MermaidFormatter{
+			config,
+		}
+	case GraphMLOutput:
+		return &GraphMLFormatter{
+			config,
+		}
+	case GEXFOutput:
+		return &GEXFFormatter{
+			config,
+		}
+	case CycloneDXOutput:
+		return &CycloneDXFormatter{
+			config,
+		}
+	case STIXOutput:
+		return &STIXFormatter{
+			config,
+		}
+	case ECSOutput:
+		return &ECSFormatter{
+			config,
+		}
+	case PrometheusOutput:
+		return &PrometheusFormatter{
+			config,
+		}
+	case AnsibleOutput:
+		return &AnsibleFormatter{
+			config,
+		}
+	case ParquetOutput:
+		return &ParquetFormatter{
+			config,
+		}
+	case SQLOutput:
+		return &SQLFormatter{
+			config,
+		}
+	case CypherOutput:
+		return &CypherFormatter{
+			config,
+		}
+	case LaTeXOutput:
+		return &LaTeXFormatter{
+			config,
+		}
+	case AsciiDocOutput:
+		return &AsciiDocFormatter{
+			config,
+		}
+	case TableOutput:
+		return &TableFormatter{
+			config,
+		}
 	}
 	return nil
 }
@@ -0,0 +1,307 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	// AnsibleINIFormat is an INI inventory format (default one)
+	AnsibleINIFormat = "ini"
+	// AnsibleYAMLFormat is a YAML inventory format
+	AnsibleYAMLFormat = "yaml"
+)
+
+// ansibleServiceGroups are groups of hosts that have certain service open,
+// a service is matched by nmap service name or by well-known port
+var ansibleServiceGroups = []struct {
+	name     string
+	services []string
+	ports    []int
+}{
+	{"ssh", []string{"ssh"}, []int{22}},
+	{"http", []string{"http", "https", "http-proxy", "http-alt"}, []int{80, 443, 8000, 8080, 8443}},
+	{"smb", []string{"microsoft-ds", "netbios-ssn"}, []int{139, 445}},
+	{"mssql", []string{"ms-sql-s"}, []int{1433}},
+}
+
+// ansibleGroupName is a valid Ansible group name
+var ansibleGroupName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AnsibleFormatter is struct defined for Ansible inventory Output use-case
+type AnsibleFormatter struct {
+	config *Config
+}
+
+// ansibleInventory contains hosts with their variables and groups with host names,
+// groups and hosts keep the order of appearance
+type ansibleInventory struct {
+	hosts  []string
+	vars   map[string]map[string]interface{}
+	groups map[string][]string
+}
+
+// ansibleCustomGroup is a group defined by the user with the expression (`name=expression`)
+type ansibleCustomGroup struct {
+	name    string
+	program *vm.Program
+}
+
+// Format the data to Ansible inventory (INI or YAML) and output it to appropriate io.Writer
+func (f *AnsibleFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.AnsibleOptions
+	inventory, err := newAnsibleInventory(&td.NMAPRun, options)
+	if err != nil {
+		return err
+	}
+	var content []byte
+	if options.Format == AnsibleYAMLFormat {
+		content, err = inventory.yaml()
+		if err != nil {
+			return err
+		}
+	} else {
+		content = []byte(inventory.ini())
+	}
+	_, err = f.config.Writer.Write(content)
+	return err
+}
+
+// newAnsibleInventory groups hosts by open services, OS family, subnet and custom expressions,
+// hosts are identified by their first IP address. Hosts that are not up are left out even if
+// `--skip-down-hosts=false` is set, since Ansible can't connect to them anyway
+func newAnsibleInventory(n *NMAPRun, options *AnsibleOutputOptions) (*ansibleInventory, error) {
+	customGroups, err := options.customGroups()
+	if err != nil {
+		return nil, err
+	}
+	inventory := &ansibleInventory{
+		vars:   map[string]map[string]interface{}{},
+		groups: map[string][]string{},
+	}
+	for i := range n.Host {
+		host := &n.Host[i]
+		name := ansibleHostName(host)
+		if name == "" || host.Status.State != "up" {
+			continue
+		}
+		inventory.hosts = append(inventory.hosts, name)
+		inventory.vars[name] = ansibleHostVars(host)
+
+		for _, g := range ansibleServiceGroups {
+			if ansibleHasService(host, g.services, g.ports) {
+				inventory.add(g.name, name)
+			}
+		}
+		if class, ok := host.OS.firstOSClass(); ok && class.OSFamily != "" {
+			inventory.add("os_"+ansibleSafeName(class.OSFamily), name)
+		}
+		if subnet := hostSubnet(host, subnetPrefix(options.SubnetPrefix)); subnet != "" {
+			inventory.add("subnet_"+ansibleSafeName(subnet), name)
+		}
+		for _, g := range customGroups {
+			// The same environment is used as in `--filter` expressions, so the syntax is identical
+			matched, err := expr.Run(g.program, NMAPRun{Host: []Host{*host}})
+			if err != nil {
+				return nil, fmt.Errorf("error evaluating ansible group %s: %v", g.name, err)
+			}
+			if ok, _ := matched.(bool); ok {
+				inventory.add(g.name, name)
+			}
+		}
+	}
+	return inventory, nil
+}
+
+// add adds the host to the group, the same host is added only once
+func (i *ansibleInventory) add(group string, host string) {
+	if slices.Contains(i.groups[group], host) {
+		return
+	}
+	i.groups[group] = append(i.groups[group], host)
+}
+
+// groupNames returns group names sorted alphabetically
+func (i *ansibleInventory) groupNames() []string {
+	names := make([]string, 0, len(i.groups))
+	for name := range i.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ini returns INI inventory, host variables are defined on host lines before any group,
+// values are JSON, which is parsed as Python literal by Ansible
+func (i *ansibleInventory) ini() string {
+	b := &strings.Builder{}
+	for _, host := range i.hosts {
+		b.WriteString(host)
+		vars := i.vars[host]
+		keys := make([]string, 0, len(vars))
+		for k := range vars {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			value, _ := json.Marshal(vars[k])
+			fmt.Fprintf(b, " %s='%s'", k, strings.ReplaceAll(string(value), "'", `'\''`))
+		}
+		b.WriteString("\n")
+	}
+	for _, group := range i.groupNames() {
+		fmt.Fprintf(b, "\n[%s]\n", group)
+		for _, host := range i.groups[group] {
+			b.WriteString(host + "\n")
+		}
+	}
+	return b.String()
+}
+
+// yaml returns YAML inventory, host variables are defined in `all` group and
+// the rest of groups are children of `all`
+func (i *ansibleInventory) yaml() ([]byte, error) {
+	hosts := map[string]interface{}{}
+	for _, host := range i.hosts {
+		hosts[host] = i.vars[host]
+	}
+	children := map[string]interface{}{}
+	for _, group := range i.groupNames() {
+		members := map[string]interface{}{}
+		for _, host := range i.groups[group] {
+			members[host] = map[string]interface{}{}
+		}
+		children[group] = map[string]interface{}{"hosts": members}
+	}
+	all := map[string]interface{}{"hosts": hosts}
+	if len(children) > 0 {
+		all["children"] = children
+	}
+	b := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(b)
+	encoder.SetIndent(2)
+	err := encoder.Encode(map[string]interface{}{"all": all})
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), encoder.Close()
+}
+
+// ansibleHostName returns the first IP address of the host, it's used as inventory hostname
+func ansibleHostName(h *Host) string {
+	for _, a := range h.HostAddress {
+		if a.AddressType != "mac" {
+			return a.Address
+		}
+	}
+	return ""
+}
+
+// ansibleHostVars returns host variables: hostnames, open ports and detected products with versions
+func ansibleHostVars(h *Host) map[string]interface{} {
+	vars := map[string]interface{}{}
+	var hostnames []string
+	for _, hostname := range h.HostNames.HostName {
+		hostnames = append(hostnames, hostname.Name)
+	}
+	if len(hostnames) > 0 {
+		vars["nmap_hostnames"] = hostnames
+	}
+	var ports []int
+	products := map[string]string{}
+	for _, p := range h.Port {
+		if p.State.State != "open" {
+			continue
+		}
+		ports = append(ports, p.PortID)
+		if p.Service.Product != "" {
+			products[fmt.Sprintf("%d/%s", p.PortID, p.Protocol)] = strings.TrimSpace(p.Service.Product + " " + p.Service.Version)
+		}
+	}
+	if len(ports) > 0 {
+		sort.Ints(ports)
+		vars["nmap_open_ports"] = ports
+	}
+	if len(products) > 0 {
+		vars["nmap_products"] = products
+	}
+	if len(h.OS.OSMatch) > 0 {
+		vars["nmap_os"] = h.OS.OSMatch[0].Name
+	}
+	return vars
+}
+
+// ansibleHasService checks whether the host has any of the services (or ports) open
+func ansibleHasService(h *Host, services []string, ports []int) bool {
+	for _, p := range h.Port {
+		if p.State.State != "open" {
+			continue
+		}
+		if slices.Contains(services, p.Service.Name) || (p.Protocol == "tcp" && slices.Contains(ports, p.PortID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ansibleSafeName converts the value to valid group name part: lowercase letters, digits and underscores
+func ansibleSafeName(v string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(v) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// customGroups parses and compiles custom group expressions (`name=expression`)
+func (o *AnsibleOutputOptions) customGroups() ([]ansibleCustomGroup, error) {
+	groups := make([]ansibleCustomGroup, 0, len(o.Groups))
+	for _, g := range o.Groups {
+		name, code, found := strings.Cut(g, "=")
+		name = strings.TrimSpace(name)
+		if !found || strings.TrimSpace(code) == "" {
+			return nil, fmt.Errorf("ansible group should be defined as name=expression: %s", g)
+		}
+		if !ansibleGroupName.MatchString(name) {
+			return nil, fmt.Errorf("not valid ansible group name: %s", name)
+		}
+		program, err := expr.Compile(fmt.Sprintf("any(Host, { %s })", code), expr.Env(NMAPRun{}), expr.AsBool())
+		if err != nil {
+			return nil, fmt.Errorf("could not compile ansible group %s expression: %v", name, err)
+		}
+		groups = append(groups, ansibleCustomGroup{name: name, program: program})
+	}
+	return groups, nil
+}
+
+// Validate checks whether inventory format, subnet prefix and custom groups are correct
+func (o *AnsibleOutputOptions) Validate() error {
+	switch o.Format {
+	case "", AnsibleINIFormat, AnsibleYAMLFormat:
+	default:
+		return fmt.Errorf("unknown ansible inventory format: %s, please choose ini/yaml", o.Format)
+	}
+	if err := validateSubnetPrefix(o.SubnetPrefix); err != nil {
+		return fmt.Errorf("wrong ansible subnet prefix: %v", err)
+	}
+	_, err := o.customGroups()
+	return err
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *AnsibleFormatter) defaultTemplateContent() string {
+	return ""
+}
@@ -0,0 +1,234 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnsibleFormatter_Format(t *testing.T) {
+	// Product with a quote, services of the groups, a host without IP address and a host that is down
+	run := NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}, {Name: "www.local"}}},
+				Status:      HostStatus{State: "up"},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.X", OSClass: []OSClass{{OSFamily: "Linux"}}}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: PortService{Name: "http", Product: "nginx", Version: "1.18.0"}},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed"}, Service: PortService{Name: "domain"}},
+					{Protocol: "tcp", PortID: 8443, State: PortState{State: "open"}, Service: PortService{Name: "https-alt", Product: "Jetty's server"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				// OS class of older nmap versions is not a part of OS match
+				OS: OS{OSClass: []OSClass{{OSFamily: "Windows"}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 445, State: PortState{State: "open"}, Service: PortService{Name: "microsoft-ds"}},
+					{Protocol: "tcp", PortID: 1433, State: PortState{State: "open"}, Service: PortService{Name: "ms-sql-s", Product: "Microsoft SQL Server 2019", Version: "15.00.2000"}},
+					{Protocol: "tcp", PortID: 3389, State: PortState{State: "open"}, Service: PortService{Name: "ms-wbt-server"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.3", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "down"},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "00:1A:2B:3C:4D:5F", AddressType: "mac"}},
+				Status:      HostStatus{State: "up"},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    AnsibleOutputOptions
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:    "INI inventory",
+			options: AnsibleOutputOptions{Groups: []string{"rdp=any(.Port, { .PortID == 3389 })"}},
+			wantOutput: `10.0.0.1 nmap_hostnames='["web.local","www.local"]' nmap_open_ports='[80,8443]' nmap_os='"Linux 5.X"' nmap_products='{"80/tcp":"nginx 1.18.0","8443/tcp":"Jetty'\''s server"}'
+10.0.1.2 nmap_open_ports='[445,1433,3389]' nmap_products='{"1433/tcp":"Microsoft SQL Server 2019 15.00.2000"}'
+
+[http]
+10.0.0.1
+
+[mssql]
+10.0.1.2
+
+[os_linux]
+10.0.0.1
+
+[os_windows]
+10.0.1.2
+
+[rdp]
+10.0.1.2
+
+[smb]
+10.0.1.2
+
+[subnet_10_0_0_0_24]
+10.0.0.1
+
+[subnet_10_0_1_0_24]
+10.0.1.2
+`,
+		},
+		{
+			name:    "YAML inventory",
+			options: AnsibleOutputOptions{Format: AnsibleYAMLFormat, Groups: []string{"rdp=any(.Port, { .PortID == 3389 })"}},
+			wantOutput: `all:
+  children:
+    http:
+      hosts:
+        10.0.0.1: {}
+    mssql:
+      hosts:
+        10.0.1.2: {}
+    os_linux:
+      hosts:
+        10.0.0.1: {}
+    os_windows:
+      hosts:
+        10.0.1.2: {}
+    rdp:
+      hosts:
+        10.0.1.2: {}
+    smb:
+      hosts:
+        10.0.1.2: {}
+    subnet_10_0_0_0_24:
+      hosts:
+        10.0.0.1: {}
+    subnet_10_0_1_0_24:
+      hosts:
+        10.0.1.2: {}
+  hosts:
+    10.0.0.1:
+      nmap_hostnames:
+        - web.local
+        - www.local
+      nmap_open_ports:
+        - 80
+        - 8443
+      nmap_os: Linux 5.X
+      nmap_products:
+        80/tcp: nginx 1.18.0
+        8443/tcp: Jetty's server
+    10.0.1.2:
+      nmap_open_ports:
+        - 445
+        - 1433
+        - 3389
+      nmap_products:
+        1433/tcp: Microsoft SQL Server 2019 15.00.2000
+`,
+		},
+		{
+			name:    "Subnet prefix",
+			options: AnsibleOutputOptions{SubnetPrefix: 8},
+			wantOutput: `10.0.0.1 nmap_hostnames='["web.local","www.local"]' nmap_open_ports='[80,8443]' nmap_os='"Linux 5.X"' nmap_products='{"80/tcp":"nginx 1.18.0","8443/tcp":"Jetty'\''s server"}'
+10.0.1.2 nmap_open_ports='[445,1433,3389]' nmap_products='{"1433/tcp":"Microsoft SQL Server 2019 15.00.2000"}'
+
+[http]
+10.0.0.1
+
+[mssql]
+10.0.1.2
+
+[os_linux]
+10.0.0.1
+
+[os_windows]
+10.0.1.2
+
+[smb]
+10.0.1.2
+
+[subnet_10_0_0_0_8]
+10.0.0.1
+10.0.1.2
+`,
+		},
+		{
+			name:    "Wrong custom group expression",
+			options: AnsibleOutputOptions{Groups: []string{"rdp=any(.Port"}},
+			wantErr: true,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &AnsibleFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					AnsibleOptions: tt.options,
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("AnsibleFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.wantOutput != string(writer.data) {
+				t.Errorf("AnsibleFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestAnsibleFormatter_Format_osFamily(t *testing.T) {
+	// OS classes are located inside of OS matches in nmap output
+	run := parseXML(t, `<?xml version="1.0" encoding="UTF-8"?>
+<nmaprun scanner="nmap" args="nmap -O 10.0.0.1" start="1700000000" version="7.94" xmloutputversion="1.05">
+<host><status state="up" reason="echo-reply" reason_ttl="63"/>
+<address addr="10.0.0.1" addrtype="ipv4"/>
+<os><portused state="open" proto="tcp" portid="22"/>
+<osmatch name="Linux 5.0 - 5.14" accuracy="98" line="67010">
+<osclass type="general purpose" vendor="Linux" osfamily="Linux" osgen="5.X" accuracy="98"><cpe>cpe:/o:linux:linux_kernel:5</cpe></osclass>
+</osmatch>
+</os>
+</host>
+</nmaprun>`)
+	writer := &jsonLinesMockedWriter{}
+	f := &AnsibleFormatter{config: &Config{Writer: writer}}
+	if err := f.Format(&TemplateData{NMAPRun: run}, ""); err != nil {
+		t.Fatalf("AnsibleFormatter.Format() error = %v", err)
+	}
+	if !strings.Contains(string(writer.data), "[os_linux]\n10.0.0.1\n") {
+		t.Errorf("AnsibleFormatter.Format() output does not contain OS family group, output = \n%s", writer.data)
+	}
+}
+
+func TestAnsibleOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options AnsibleOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: AnsibleOutputOptions{}, wantErr: false},
+		{name: "YAML with custom group", options: AnsibleOutputOptions{Format: "yaml", Groups: []string{"web = .Status.State == 'up'"}}, wantErr: false},
+		{name: "Wrong format", options: AnsibleOutputOptions{Format: "toml"}, wantErr: true},
+		{name: "Wrong prefix", options: AnsibleOutputOptions{SubnetPrefix: 33}, wantErr: true},
+		{name: "Missing expression", options: AnsibleOutputOptions{Groups: []string{"web"}}, wantErr: true},
+		{name: "Wrong group name", options: AnsibleOutputOptions{Groups: []string{"web-servers=true"}}, wantErr: true},
+		{name: "Expression is not boolean", options: AnsibleOutputOptions{Groups: []string{"web=.Port"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AnsibleOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
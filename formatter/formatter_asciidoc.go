@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	// Used in this place to have all required functionality within one binary file. No need for separate folders/files, just embed template
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// AsciiDocFormatter is a formatter struct used to deliver AsciiDoc document
+type AsciiDocFormatter struct {
+	config *Config
+}
+
+// AsciiDocTemplate variable is used to store asciidoc.tmpl embed file contents
+//
+//go:embed resources/templates/asciidoc.tmpl
+var AsciiDocTemplate string
+
+var (
+	// asciiDocPlainText matches text that is not changed by AsciiDoc inline substitutions
+	asciiDocPlainText = regexp.MustCompile(`^[A-Za-z0-9 .,/()@%=-]*$`)
+	// asciiDocReplacements matches character replacements (em dash, ellipsis, copyright, etc.)
+	asciiDocReplacements = regexp.MustCompile(`--|\.\.\.|\((C|R|TM)\)`)
+	// asciiDocIDCharacters matches characters that are not allowed in section id
+	asciiDocIDCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+	// asciiDocDelimiterLine matches a line that could close a listing block
+	asciiDocDelimiterLine = regexp.MustCompile(`^-{4,}$`)
+)
+
+// Format the data and output it to appropriate io.Writer
+func (f *AsciiDocFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	tmpl := template.New("asciidoc")
+	f.defineTemplateFunctions(tmpl)
+	tmpl, err = tmpl.Parse(templateContent)
+	if err != nil {
+		return
+	}
+	return tmpl.Execute(f.config.Writer, td)
+}
+
+func (f *AsciiDocFormatter) defaultTemplateContent() string {
+	return AsciiDocTemplate
+}
+
+func (f *AsciiDocFormatter) defineTemplateFunctions(tmpl *template.Template) {
+	tmpl.Funcs(
+		template.FuncMap{
+			"adoc":         asciiDocEscape,
+			"adoc_cell":    asciiDocCell,
+			"adoc_listing": asciiDocListing,
+			"adoc_title":   asciiDocHostTitle,
+			"adoc_id":      asciiDocHostID,
+		},
+	)
+}
+
+// asciiDocEscape returns the text as is if AsciiDoc would not change it, otherwise the text
+// is wrapped in passthrough macro, which only escapes HTML special characters; trailing backslashes
+// are kept outside of the macro, so they don't escape the closing bracket
+func asciiDocEscape(v string) string {
+	if asciiDocPlainText.MatchString(v) && !asciiDocReplacements.MatchString(v) {
+		return v
+	}
+	text := strings.TrimRight(v, `\`)
+	return "pass:c[" + strings.ReplaceAll(text, "]", `\]`) + "]" + v[len(text):]
+}
+
+// asciiDocCell escapes the text that is used in the table cell, cell separator is escaped as well
+func asciiDocCell(v string) string {
+	return strings.ReplaceAll(asciiDocEscape(v), "|", `\|`)
+}
+
+// asciiDocListing returns listing block with the text, that is displayed verbatim, block delimiter
+// is made longer than any delimiter-like line of the text
+func asciiDocListing(v string) string {
+	v = strings.Trim(strings.ReplaceAll(v, "\r\n", "\n"), "\n")
+	delimiterLength := 4
+	for _, line := range strings.Split(v, "\n") {
+		if asciiDocDelimiterLine.MatchString(line) && len(line) >= delimiterLength {
+			delimiterLength = len(line) + 1
+		}
+	}
+	delimiter := strings.Repeat("-", delimiterLength)
+	return fmt.Sprintf("[subs=\"specialchars\"]\n%s\n%s\n%s", delimiter, v, delimiter)
+}
+
+// asciiDocHostTitle returns escaped host section title
+func asciiDocHostTitle(h *Host) string {
+	title := h.JoinedAddresses("/")
+	for i := range h.HostNames.HostName {
+		title += fmt.Sprintf(" / %s", h.HostNames.HostName[i].Name)
+	}
+	title += fmt.Sprintf(" (%s)", h.Status.State)
+	return asciiDocEscape(title)
+}
+
+// asciiDocHostID returns host section id, which is used in table of contents
+func asciiDocHostID(h *Host) string {
+	return "host-" + strings.Trim(asciiDocIDCharacters.ReplaceAllString(strings.ToLower(h.JoinedAddresses("-")), "-"), "-")
+}
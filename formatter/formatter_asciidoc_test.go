@@ -0,0 +1,203 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsciiDocFormatter_Format(t *testing.T) {
+	// Values with characters that have to be escaped
+	run := NMAPRun{
+		Scanner:  "nmap",
+		Args:     "nmap -sV -O --traceroute 10.0.0.0/23",
+		Start:    1700000000,
+		StartStr: "Tue Nov 14 22:13:20 2023",
+		Version:  "7.94",
+		RunStats: RunStats{
+			Finished: Finished{Time: 1700000012, Elapsed: 12.35},
+			Hosts:    StatHosts{Up: 2, Down: 1, Total: 3},
+		},
+		Host: []Host{
+			{
+				StartTime:   1700000001,
+				EndTime:     1700000011,
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local", Type: "PTR"}}},
+				Status:      HostStatus{State: "up", Reason: "syn-ack"},
+				Distance:    Distance{Value: 2},
+				OS: OS{
+					OSMatch: []OSMatch{{Name: "Linux 5.X", Accuracy: "95", Line: "100"}},
+					OSClass: []OSClass{{OSFamily: "Linux"}},
+				},
+				Trace: Trace{
+					Port:     80,
+					Protocol: "tcp",
+					Hops:     []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.5}, {TTL: 2, IPAddr: "10.0.0.1", RTT: 1.25}},
+				},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "*nix | server", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"}},
+						Script:   []Script{{ID: "http-title", Output: "\n  Title: <Welcome>\n----\n"}},
+					},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed", Reason: "port-unreach"}, Service: PortService{Name: "domain"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up", Reason: "echo-reply"},
+				OS:          OS{OSClass: []OSClass{{OSFamily: "Windows"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.75}, {TTL: 2, IPAddr: "10.0.1.2", RTT: 1.5}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 445, State: PortState{State: "open", Reason: "syn-ack"}, Service: PortService{Name: "microsoft-ds"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.3", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "down", Reason: "no-response"},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      AsciiDocOutputOptions
+		template     string
+		err          error
+		wantErr      bool
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "Default template",
+			wantContains: []string{
+				"= NMAP Scan Result: pass:c[Tue Nov 14 22:13:20 2023]\n\n== TOC\n\n",
+				"* <<scan-summary,Scan Summary>>\n* <<host-10-0-0-1-00-1a-2b-3c-4d-5e,pass:c[10.0.0.1 / 00:1A:2B:3C:4D:5E / web.local (up)]>>\n* <<host-10-0-1-2,10.0.1.2 (up)>>\n* <<host-10-0-0-3,10.0.0.3 (down)>>\n\n'''\n",
+				"| Arguments | `pass:c[nmap -sV -O --traceroute 10.0.0.0/23]`\n",
+				"[#host-10-0-0-1-00-1a-2b-3c-4d-5e]\n== pass:c[10.0.0.1 / 00:1A:2B:3C:4D:5E / web.local (up)]\n",
+				"| Hostnames | web.local (PTR)\n| OS | *Linux 5.X (95%)*\n|===\n",
+				"| 80 | tcp | open | http | syn-ack | pass:c[*nix \\| server] | 1.18.0 | \n",
+				"=== Traceroute information\n\n_Generated traceroute data using 80/tcp_\n",
+				"| 1 | 0.5 | 10.0.0.254 | \n| 2 | 1.25 | 10.0.0.1 | \n|===\n",
+				"=== Misc Metrics\n",
+				"==== PORT 80\n\n*Script ID:* `http-title`\n\n[subs=\"specialchars\"]\n-----\n  Title: <Welcome>\n----\n-----\n",
+			},
+		},
+		{
+			name: "Skip options",
+			options: AsciiDocOutputOptions{
+				SkipHeader:      true,
+				SkipTOC:         true,
+				SkipSummary:     true,
+				SkipTraceroute:  true,
+				SkipMetrics:     true,
+				SkipPortScripts: true,
+			},
+			wantContains: []string{"== pass:c[10.0.0.1 / 00:1A:2B:3C:4D:5E / web.local (up)]\n", "=== Ports\n"},
+			wantMissing: []string{
+				"= NMAP Scan Result",
+				"== TOC",
+				"== Scan Summary",
+				"=== Traceroute information",
+				"=== Misc Metrics",
+				"=== Scripts",
+			},
+		},
+		{
+			name:         "Custom template",
+			template:     `{{ range .NMAPRun.Host }}* <<{{ adoc_id . }}>>{{ end }}`,
+			wantContains: []string{"* <<host-10-0-0-1-00-1a-2b-3c-4d-5e>>* <<host-10-0-1-2>>"},
+		},
+		{
+			name:     "Wrong template",
+			template: `{{ .Unknown `,
+			wantErr:  true,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &AsciiDocFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					AsciiDocOptions: tt.options,
+				},
+			}
+			templateContent := tt.template
+			if templateContent == "" {
+				templateContent = f.defaultTemplateContent()
+			}
+			if err := f.Format(td, templateContent); (err != nil) != tt.wantErr {
+				t.Errorf("AsciiDocFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("AsciiDocFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("AsciiDocFormatter.Format() output should not contain %q", missing)
+				}
+			}
+		})
+	}
+}
+
+func Test_asciiDocEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{name: "Plain text", v: "OpenSSH 8.9p1 (protocol 2.0)", want: "OpenSSH 8.9p1 (protocol 2.0)"},
+		{name: "Empty", v: "", want: ""},
+		{name: "Formatting characters", v: "*bold* _italic_", want: "pass:c[*bold* _italic_]"},
+		{name: "Replacements", v: "nmap --top-ports 100", want: "pass:c[nmap --top-ports 100]"},
+		{name: "Copyright", v: "Product (C) 2023", want: "pass:c[Product (C) 2023]"},
+		{name: "Closing bracket", v: "a[1]", want: `pass:c[a[1\]]`},
+		{name: "Trailing backslash", v: `C:\web\`, want: `pass:c[C:\web]\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := asciiDocEscape(tt.v); got != tt.want {
+				t.Errorf("asciiDocEscape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_asciiDocListing(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{name: "Simple", v: "\nline\n", want: "[subs=\"specialchars\"]\n----\nline\n----"},
+		{name: "Delimiter in the text", v: "a\n------\nb", want: "[subs=\"specialchars\"]\n-------\na\n------\nb\n-------"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := asciiDocListing(tt.v); got != tt.want {
+				t.Errorf("asciiDocListing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_asciiDocHostID(t *testing.T) {
+	h := &Host{HostAddress: []HostAddress{{Address: "fe80::1", AddressType: "ipv6"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}}}
+	if got := asciiDocHostID(h); got != "host-fe80-1-00-1a-2b-3c-4d-5e" {
+		t.Errorf("asciiDocHostID() = %v", got)
+	}
+}
@@ -3,8 +3,44 @@ package formatter
 import (
 	"encoding/csv"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
+// csvValueDelimiter is used to join multiple values (CPE, OS matches, et cetera) within one cell
+const csvValueDelimiter = "; "
+
+// CSVDefaultColumns is a list of columns used when no columns were chosen by the user
+var CSVDefaultColumns = []string{"ip", "port", "protocol", "state", "service", "reason", "product", "version", "extra_info"}
+
+// CSVDefaultFlatColumns is a list of columns used in flat mode when no columns were chosen by the user
+var CSVDefaultFlatColumns = []string{"ip", "host_state", "port", "protocol", "state", "service", "reason", "product", "version", "extra_info"}
+
+// csvColumn describes a single CSV column: its header title and how the value is taken from host and port
+type csvColumn struct {
+	title string
+	host  func(h *Host, flat bool) string
+	port  func(p *Port) string
+}
+
+// csvColumns contains all columns that can be chosen with CSVOutputOptions.Columns
+var csvColumns = map[string]csvColumn{
+	"ip":         {title: "IP", host: csvHostAddress},
+	"hostnames":  {title: "Hostnames", host: func(h *Host, flat bool) string { return h.JoinedHostNames(csvValueDelimiter) }},
+	"host_state": {title: "Host State", host: func(h *Host, flat bool) string { return h.Status.State }},
+	"os":         {title: "OS", host: csvHostOS},
+	"port":       {title: "Port", port: func(p *Port) string { return fmt.Sprint(p.PortID) }},
+	"protocol":   {title: "Protocol", port: func(p *Port) string { return p.Protocol }},
+	"state":      {title: "State", port: func(p *Port) string { return p.State.State }},
+	"service":    {title: "Service", port: func(p *Port) string { return p.Service.Name }},
+	"reason":     {title: "Reason", port: func(p *Port) string { return p.State.Reason }},
+	"product":    {title: "Product", port: func(p *Port) string { return p.Service.Product }},
+	"version":    {title: "Version", port: func(p *Port) string { return p.Service.Version }},
+	"extra_info": {title: "Extra info", port: func(p *Port) string { return p.Service.ExtraInfo }},
+	"cpe":        {title: "CPE", port: func(p *Port) string { return strings.Join(p.Service.CPE, csvValueDelimiter) }},
+	"scripts":    {title: "Scripts", port: csvPortScripts},
+}
+
 // CSVFormatter is struct defined for CSV Output use-case
 type CSVFormatter struct {
 	config *Config
@@ -12,37 +48,164 @@ type CSVFormatter struct {
 
 // Format the data to CSV and output it to appropriate io.Writer
 func (f *CSVFormatter) Format(td *TemplateData, templateContent string) (err error) {
-	return csv.NewWriter(f.config.Writer).WriteAll(f.convert(td))
+	comma, err := td.OutputOptions.CSVOptions.Comma()
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(f.config.Writer)
+	writer.Comma = comma
+	return writer.WriteAll(f.convert(td))
 }
 
 // convert uses NMAPRun struct to convert all data to [][]string type
 func (f *CSVFormatter) convert(td *TemplateData) (data [][]string) {
-	data = append(data, []string{"IP", "Port", "Protocol", "State", "Service", "Reason", "Product", "Version", "Extra info"})
+	options := td.OutputOptions.CSVOptions
+	columns := options.columns()
+
+	if !options.SkipHeader {
+		header := make([]string, len(columns))
+		for i := range columns {
+			header[i] = columns[i].title
+		}
+		data = append(data, header)
+	}
+
+	// In non-flat mode host and port rows are separate, port rows would be empty
+	// if only host columns are chosen, so they are left out
+	portRows := csvHasPortColumns(columns)
 	for i := range td.NMAPRun.Host {
 		host := &td.NMAPRun.Host[i]
-		address := fmt.Sprintf("%s (%s)", host.JoinedAddresses("/"), host.Status.State)
-		data = append(data, []string{address, "", "", "", "", "", "", "", ""})
+		if options.Flat {
+			data = append(data, f.flatRows(host, columns)...)
+			continue
+		}
+		data = append(data, csvRow(columns, host, nil, false))
+		if !portRows {
+			continue
+		}
 		for j := range host.Port {
-			port := &host.Port[j]
-			data = append(
-				data,
-				[]string{
-					"",
-					fmt.Sprint(port.PortID),
-					port.Protocol,
-					port.State.State,
-					port.Service.Name,
-					port.State.Reason,
-					port.Service.Product,
-					port.Service.Version,
-					port.Service.ExtraInfo,
-				},
-			)
+			data = append(data, csvRow(columns, nil, &host.Port[j], false))
 		}
 	}
 	return
 }
 
+// csvHasPortColumns returns whether any of the columns is filled in from port
+func csvHasPortColumns(columns []csvColumn) bool {
+	for i := range columns {
+		if columns[i].port != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// flatRows returns one row per port, every row repeats host data. Host without any ports
+// is still listed in a single row with empty port cells
+func (f *CSVFormatter) flatRows(host *Host, columns []csvColumn) (rows [][]string) {
+	if len(host.Port) == 0 {
+		return [][]string{csvRow(columns, host, nil, true)}
+	}
+	for j := range host.Port {
+		rows = append(rows, csvRow(columns, host, &host.Port[j], true))
+	}
+	return rows
+}
+
+// csvRow fills in the row with host and port values, if host or port is nil the cells are left empty
+func csvRow(columns []csvColumn, host *Host, port *Port, flat bool) []string {
+	row := make([]string, len(columns))
+	for i := range columns {
+		if host != nil && columns[i].host != nil {
+			row[i] = columns[i].host(host, flat)
+		}
+		if port != nil && columns[i].port != nil {
+			row[i] = columns[i].port(port)
+		}
+	}
+	return row
+}
+
+// csvHostAddress returns joined host addresses, in non-flat mode host state is appended as well
+func csvHostAddress(h *Host, flat bool) string {
+	if flat {
+		return h.JoinedAddresses("/")
+	}
+	return fmt.Sprintf("%s (%s)", h.JoinedAddresses("/"), h.Status.State)
+}
+
+// csvHostOS returns all OS matches of the host joined together
+func csvHostOS(h *Host, flat bool) string {
+	names := make([]string, len(h.OS.OSMatch))
+	for i := range h.OS.OSMatch {
+		names[i] = h.OS.OSMatch[i].Name
+	}
+	return strings.Join(names, csvValueDelimiter)
+}
+
+// csvPortScripts returns all port script outputs, one script per line
+func csvPortScripts(p *Port) string {
+	scripts := make([]string, len(p.Script))
+	for i := range p.Script {
+		scripts[i] = fmt.Sprintf("%s: %s", p.Script[i].ID, strings.TrimSpace(p.Script[i].Output))
+	}
+	return strings.Join(scripts, "\n")
+}
+
+// Comma returns a delimiter rune that is used to separate CSV fields, by default it's a comma,
+// `\t` value is used to define tab delimiter
+func (o *CSVOutputOptions) Comma() (rune, error) {
+	switch o.Delimiter {
+	case "":
+		return ',', nil
+	case `\t`:
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(o.Delimiter) != 1 {
+		return 0, fmt.Errorf("csv delimiter should be a single character: %q", o.Delimiter)
+	}
+	r, _ := utf8.DecodeRuneInString(o.Delimiter)
+	if r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("csv delimiter is not allowed: %q", o.Delimiter)
+	}
+	return r, nil
+}
+
+// ValidateColumns checks whether all chosen columns exist, in non-flat mode at least one host column
+// has to be chosen, otherwise port rows of different hosts could not be told apart
+func (o *CSVOutputOptions) ValidateColumns() error {
+	hostColumn := len(o.Columns) == 0 || o.Flat
+	for _, c := range o.Columns {
+		column, ok := csvColumns[c]
+		if !ok {
+			return fmt.Errorf("unknown csv column: %s", c)
+		}
+		hostColumn = hostColumn || column.host != nil
+	}
+	if !hostColumn {
+		return fmt.Errorf("csv columns should contain a host column (ip, hostnames, host_state or os) unless flat mode is used")
+	}
+	return nil
+}
+
+// columns returns a list of columns chosen by the user or default ones, unknown columns are skipped
+func (o *CSVOutputOptions) columns() []csvColumn {
+	names := o.Columns
+	if len(names) == 0 {
+		names = CSVDefaultColumns
+		if o.Flat {
+			names = CSVDefaultFlatColumns
+		}
+	}
+	columns := []csvColumn{}
+	for _, name := range names {
+		if c, ok := csvColumns[name]; ok {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
 func (f *CSVFormatter) defaultTemplateContent() string {
 	return HTMLSimpleTemplate
 }
@@ -550,6 +550,229 @@ func TestCSVFormatter_convert(t *testing.T) {
 	}
 }
 
+func TestCSVFormatter_convertOptions(t *testing.T) {
+	hosts := []Host{
+		{
+			HostAddress: []HostAddress{
+				{
+					Address: "127.0.0.1",
+				},
+			},
+			HostNames: HostNames{
+				HostName: []HostName{
+					{Name: "localhost"},
+					{Name: "example.local"},
+				},
+			},
+			Status: HostStatus{
+				State: "up",
+			},
+			OS: OS{
+				OSMatch: []OSMatch{
+					{Name: "Linux 5.0"},
+				},
+			},
+			Port: []Port{
+				{
+					Protocol: "tcp",
+					PortID:   80,
+					State: PortState{
+						State:  "open",
+						Reason: "syn-ack",
+					},
+					Service: PortService{
+						Name:    "http",
+						Product: "nginx",
+						Version: "1.21.1",
+						CPE:     []string{"cpe:/a:igor_sysoev:nginx:1.21.1", "cpe:/o:linux:linux_kernel"},
+					},
+					Script: []Script{
+						{ID: "http-title", Output: "Welcome\n"},
+						{ID: "http-server-header", Output: "nginx"},
+					},
+				},
+				{
+					Protocol: "tcp",
+					PortID:   22,
+					State: PortState{
+						State: "closed",
+					},
+				},
+			},
+		},
+		{
+			HostAddress: []HostAddress{
+				{
+					Address: "192.168.1.1",
+				},
+			},
+			Status: HostStatus{
+				State: "down",
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		options  CSVOutputOptions
+		wantData [][]string
+	}{
+		{
+			name: "Flat with default columns",
+			options: CSVOutputOptions{
+				Flat: true,
+			},
+			wantData: [][]string{
+				{"IP", "Host State", "Port", "Protocol", "State", "Service", "Reason", "Product", "Version", "Extra info"},
+				{"127.0.0.1", "up", "80", "tcp", "open", "http", "syn-ack", "nginx", "1.21.1", ""},
+				{"127.0.0.1", "up", "22", "tcp", "closed", "", "", "", "", ""},
+				{"192.168.1.1", "down", "", "", "", "", "", "", "", ""},
+			},
+		},
+		{
+			name: "Flat with custom columns and no header",
+			options: CSVOutputOptions{
+				Flat:       true,
+				SkipHeader: true,
+				Columns:    []string{"ip", "hostnames", "port", "cpe", "os", "scripts"},
+			},
+			wantData: [][]string{
+				{"127.0.0.1", "localhost; example.local", "80", "cpe:/a:igor_sysoev:nginx:1.21.1; cpe:/o:linux:linux_kernel", "Linux 5.0", "http-title: Welcome\nhttp-server-header: nginx"},
+				{"127.0.0.1", "localhost; example.local", "22", "", "Linux 5.0", ""},
+				{"192.168.1.1", "", "", "", "", ""},
+			},
+		},
+		{
+			name: "Grouped with custom columns",
+			options: CSVOutputOptions{
+				Columns: []string{"ip", "hostnames", "port", "service"},
+			},
+			wantData: [][]string{
+				{"IP", "Hostnames", "Port", "Service"},
+				{"127.0.0.1 (up)", "localhost; example.local", "", ""},
+				{"", "", "80", "http"},
+				{"", "", "22", ""},
+				{"192.168.1.1 (down)", "", "", ""},
+			},
+		},
+		{
+			name: "Grouped with host columns only",
+			options: CSVOutputOptions{
+				Columns: []string{"ip", "os"},
+			},
+			wantData: [][]string{
+				{"IP", "OS"},
+				{"127.0.0.1 (up)", "Linux 5.0"},
+				{"192.168.1.1 (down)", ""},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &CSVFormatter{}
+			td := &TemplateData{
+				NMAPRun: NMAPRun{
+					Host: hosts,
+				},
+				OutputOptions: OutputOptions{
+					CSVOptions: tt.options,
+				},
+			}
+			if gotData := f.convert(td); !reflect.DeepEqual(gotData, tt.wantData) {
+				t.Errorf("CSVFormatter.convert() = %q, want %q", gotData, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestCSVOutputOptions_Comma(t *testing.T) {
+	tests := []struct {
+		name      string
+		delimiter string
+		want      rune
+		wantErr   bool
+	}{
+		{
+			name:      "Default delimiter",
+			delimiter: "",
+			want:      ',',
+		},
+		{
+			name:      "Semicolon",
+			delimiter: ";",
+			want:      ';',
+		},
+		{
+			name:      "Tab",
+			delimiter: `\t`,
+			want:      '\t',
+		},
+		{
+			name:      "Multiple characters",
+			delimiter: ";;",
+			wantErr:   true,
+		},
+		{
+			name:      "Quote is not allowed",
+			delimiter: `"`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &CSVOutputOptions{Delimiter: tt.delimiter}
+			got, err := o.Comma()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CSVOutputOptions.Comma() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CSVOutputOptions.Comma() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVOutputOptions_ValidateColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		options CSVOutputOptions
+		wantErr bool
+	}{
+		{
+			name:    "Default columns",
+			options: CSVOutputOptions{},
+			wantErr: false,
+		},
+		{
+			name:    "Host and port columns",
+			options: CSVOutputOptions{Columns: []string{"hostnames", "port"}},
+			wantErr: false,
+		},
+		{
+			name:    "Unknown column",
+			options: CSVOutputOptions{Columns: []string{"ip", "unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "Port columns only",
+			options: CSVOutputOptions{Columns: []string{"port", "state"}},
+			wantErr: true,
+		},
+		{
+			name:    "Port columns only in flat mode",
+			options: CSVOutputOptions{Flat: true, Columns: []string{"port", "state"}},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.ValidateColumns(); (err != nil) != tt.wantErr {
+				t.Errorf("CSVOutputOptions.ValidateColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCSVFormatter_Format(t *testing.T) {
 	writer := &csvMockedWriter{}
 	type args struct {
@@ -583,6 +806,68 @@ func TestCSVFormatter_Format(t *testing.T) {
 			wantErr:    false,
 			wantOutput: "IP,Port,Protocol,State,Service,Reason,Product,Version,Extra info\n",
 		},
+		{
+			name: "Custom delimiter",
+			f: &CSVFormatter{
+				config: &Config{
+					Writer: writer,
+				},
+			},
+			args: args{
+				td: &TemplateData{
+					NMAPRun: NMAPRun{
+						Host: []Host{},
+					},
+					OutputOptions: OutputOptions{
+						CSVOptions: CSVOutputOptions{
+							Delimiter: ";",
+							Columns:   []string{"ip", "port"},
+						},
+					},
+				},
+			},
+			wantErr:    false,
+			wantOutput: "IP;Port\n",
+		},
+		{
+			name: "Unknown column is skipped",
+			f: &CSVFormatter{
+				config: &Config{
+					Writer: writer,
+				},
+			},
+			args: args{
+				td: &TemplateData{
+					OutputOptions: OutputOptions{
+						CSVOptions: CSVOutputOptions{
+							Columns: []string{"ip", "unknown"},
+						},
+					},
+				},
+			},
+			wantErr:    false,
+			wantOutput: "IP\n",
+		},
+		{
+			name: "Wrong delimiter",
+			f: &CSVFormatter{
+				config: &Config{
+					Writer: writer,
+				},
+			},
+			args: args{
+				td: &TemplateData{
+					OutputOptions: OutputOptions{
+						CSVOptions: CSVOutputOptions{
+							Delimiter: ";;",
+						},
+					},
+				},
+			},
+			wantErr: true,
+			// Nothing is written, writer keeps the output of the previous case
+			wantOutput: "IP\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
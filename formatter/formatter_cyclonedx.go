@@ -0,0 +1,218 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// CycloneDXSpecVersion is a version of CycloneDX specification that output conforms to
+	CycloneDXSpecVersion = "1.5"
+	// CycloneDXPropertyPrefix is a namespace of all custom component properties
+	CycloneDXPropertyPrefix = "nmap:"
+)
+
+// cycloneDXRefUnsafe matches characters that are replaced in host keys of `bom-ref`
+var cycloneDXRefUnsafe = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// CycloneDXFormatter is struct defined for CycloneDX JSON Output use-case, every discovered
+// service and operating system becomes a component, so it can be correlated with known vulnerabilities
+type CycloneDXFormatter struct {
+	config *Config
+}
+
+// CycloneDXBOM is a root CycloneDX document (Bill of Materials)
+type CycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata contains time of the scan and tools that produced the document
+type CycloneDXMetadata struct {
+	Timestamp string         `json:"timestamp,omitempty"`
+	Tools     CycloneDXTools `json:"tools"`
+}
+
+// CycloneDXTools contains a list of tools (nmap & nmap-formatter)
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is a software component: a service (application) or an operating system
+type CycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Publisher  string              `json:"publisher,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	CPE        string              `json:"cpe,omitempty"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXProperty is a name-value pair, names are prefixed with CycloneDXPropertyPrefix
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Format the data to CycloneDX JSON and output it to appropriate io.Writer
+func (f *CycloneDXFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	encoder := json.NewEncoder(f.config.Writer)
+	if td.OutputOptions.JSONOptions.PrettyPrint {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(f.bom(&td.NMAPRun))
+}
+
+// bom converts scan results to CycloneDX document
+func (f *CycloneDXFormatter) bom(n *NMAPRun) *CycloneDXBOM {
+	bom := &CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + generateUUID(),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Tools: CycloneDXTools{
+				Components: []CycloneDXComponent{
+					{Type: "application", Name: n.Scanner, Version: n.Version},
+					{Type: "application", Name: "nmap-formatter", Version: f.config.CurrentVersion},
+				},
+			},
+		},
+		Components: []CycloneDXComponent{},
+	}
+	if n.Start != 0 {
+		bom.Metadata.Timestamp = time.Unix(int64(n.Start), 0).UTC().Format(time.RFC3339)
+	}
+	keys := cycloneDXHostKeys(n.Host)
+	for i := range n.Host {
+		host := &n.Host[i]
+		if c, ok := cycloneDXOSComponent(host, keys[i]); ok {
+			bom.Components = append(bom.Components, c)
+		}
+		for j := range host.Port {
+			if c, ok := cycloneDXServiceComponent(host, keys[i], &host.Port[j]); ok {
+				bom.Components = append(bom.Components, c)
+			}
+		}
+	}
+	return bom
+}
+
+// cycloneDXHostKeys returns keys that identify hosts in `bom-ref` of their components, the first
+// address is used as a key, hosts without address or with repeated address are identified by position
+func cycloneDXHostKeys(hosts []Host) []string {
+	keys := make([]string, len(hosts))
+	seen := map[string]bool{}
+	for i := range hosts {
+		key := ""
+		if len(hosts[i].HostAddress) > 0 {
+			key = cycloneDXRefUnsafe.ReplaceAllString(hosts[i].HostAddress[0].Address, "-")
+		}
+		if key == "" || seen[key] {
+			key = fmt.Sprintf("host%d", i)
+		}
+		seen[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+// cycloneDXServiceComponent returns a component of the service, services without detected
+// product and CPE are skipped, since nothing can be correlated with them
+func cycloneDXServiceComponent(h *Host, key string, p *Port) (CycloneDXComponent, bool) {
+	if p.Service.Product == "" && len(p.Service.CPE) == 0 {
+		return CycloneDXComponent{}, false
+	}
+	name := p.Service.Product
+	if name == "" {
+		name = p.Service.Name
+	}
+	properties := cycloneDXHostProperties(h)
+	properties = append(
+		properties,
+		CycloneDXProperty{Name: CycloneDXPropertyPrefix + "port", Value: strconv.Itoa(p.PortID)},
+		CycloneDXProperty{Name: CycloneDXPropertyPrefix + "protocol", Value: p.Protocol},
+		CycloneDXProperty{Name: CycloneDXPropertyPrefix + "port:state", Value: p.State.State},
+	)
+	properties = cycloneDXAppendProperty(properties, "service:name", p.Service.Name)
+	properties = cycloneDXAppendProperty(properties, "service:extrainfo", p.Service.ExtraInfo)
+	c := CycloneDXComponent{
+		Type:    "application",
+		BOMRef:  fmt.Sprintf("service:%s:%s/%d", key, p.Protocol, p.PortID),
+		Name:    name,
+		Version: p.Service.Version,
+	}
+	c.CPE, c.Properties = cycloneDXCPE(p.Service.CPE, properties)
+	return c, true
+}
+
+// cycloneDXOSComponent returns a component of the operating system with the best accuracy,
+// OS class provides vendor, generation (version) and CPE
+func cycloneDXOSComponent(h *Host, key string) (CycloneDXComponent, bool) {
+	class, hasClass := h.OS.firstOSClass()
+	if len(h.OS.OSMatch) == 0 && !hasClass {
+		return CycloneDXComponent{}, false
+	}
+	c := CycloneDXComponent{
+		Type:   "operating-system",
+		BOMRef: "os:" + key,
+	}
+	properties := cycloneDXHostProperties(h)
+	if len(h.OS.OSMatch) > 0 {
+		c.Name = h.OS.OSMatch[0].Name
+		properties = cycloneDXAppendProperty(properties, "os:accuracy", h.OS.OSMatch[0].Accuracy)
+	}
+	var cpe []string
+	if hasClass {
+		if c.Name == "" {
+			c.Name = class.OSFamily
+		}
+		c.Publisher = class.Vendor
+		c.Version = class.OSGen
+		properties = cycloneDXAppendProperty(properties, "os:family", class.OSFamily)
+		cpe = class.CPE
+	}
+	c.CPE, c.Properties = cycloneDXCPE(cpe, properties)
+	return c, true
+}
+
+// cycloneDXHostProperties returns properties of the host that component is located on
+func cycloneDXHostProperties(h *Host) []CycloneDXProperty {
+	properties := []CycloneDXProperty{}
+	properties = cycloneDXAppendProperty(properties, "host:address", h.JoinedAddresses("/"))
+	properties = cycloneDXAppendProperty(properties, "host:hostname", h.JoinedHostNames("/"))
+	return properties
+}
+
+// cycloneDXCPE returns the first CPE for the component, the rest of them are added as properties,
+// since CycloneDX allows only one CPE per component
+func cycloneDXCPE(cpe []string, properties []CycloneDXProperty) (string, []CycloneDXProperty) {
+	if len(cpe) == 0 {
+		return "", properties
+	}
+	for _, c := range cpe[1:] {
+		properties = cycloneDXAppendProperty(properties, "cpe", c)
+	}
+	return cpe[0], properties
+}
+
+// cycloneDXAppendProperty appends prefixed property only if the value is not empty
+func cycloneDXAppendProperty(properties []CycloneDXProperty, name string, value string) []CycloneDXProperty {
+	if value == "" {
+		return properties
+	}
+	return append(properties, CycloneDXProperty{Name: CycloneDXPropertyPrefix + name, Value: value})
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *CycloneDXFormatter) defaultTemplateContent() string {
+	return ""
+}
@@ -0,0 +1,152 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCycloneDXFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Version: "7.94",
+		Start:   1700000000,
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				OS: OS{
+					OSMatch: []OSMatch{
+						{
+							Name:     "Linux 5.0 - 5.4",
+							Accuracy: "98",
+							OSClass: []OSClass{
+								{Vendor: "Linux", OSFamily: "Linux", OSGen: "5.X", CPE: []string{"cpe:/o:linux:linux_kernel:5"}},
+							},
+						},
+					},
+				},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open"},
+						Service: PortService{
+							Name:      "http",
+							Product:   "nginx",
+							Version:   "1.18.0",
+							ExtraInfo: "Ubuntu",
+							CPE:       []string{"cpe:/a:igor_sysoev:nginx:1.18.0", "cpe:/o:linux:linux_kernel"},
+						},
+					},
+					{
+						Protocol: "tcp",
+						PortID:   8080,
+						State:    PortState{State: "open"},
+						Service:  PortService{Name: "http-proxy"},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+		want    []CycloneDXComponent
+	}{
+		{
+			name: "Services and OS",
+			want: []CycloneDXComponent{
+				{
+					Type:      "operating-system",
+					BOMRef:    "os:192.168.1.10",
+					Publisher: "Linux",
+					Name:      "Linux 5.0 - 5.4",
+					Version:   "5.X",
+					CPE:       "cpe:/o:linux:linux_kernel:5",
+					Properties: []CycloneDXProperty{
+						{Name: "nmap:host:address", Value: "192.168.1.10"},
+						{Name: "nmap:host:hostname", Value: "web.local"},
+						{Name: "nmap:os:accuracy", Value: "98"},
+						{Name: "nmap:os:family", Value: "Linux"},
+					},
+				},
+				{
+					Type:    "application",
+					BOMRef:  "service:192.168.1.10:tcp/80",
+					Name:    "nginx",
+					Version: "1.18.0",
+					CPE:     "cpe:/a:igor_sysoev:nginx:1.18.0",
+					Properties: []CycloneDXProperty{
+						{Name: "nmap:host:address", Value: "192.168.1.10"},
+						{Name: "nmap:host:hostname", Value: "web.local"},
+						{Name: "nmap:port", Value: "80"},
+						{Name: "nmap:protocol", Value: "tcp"},
+						{Name: "nmap:port:state", Value: "open"},
+						{Name: "nmap:service:name", Value: "http"},
+						{Name: "nmap:service:extrainfo", Value: "Ubuntu"},
+						{Name: "nmap:cpe", Value: "cpe:/o:linux:linux_kernel"},
+					},
+				},
+			},
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &CycloneDXFormatter{config: &Config{Writer: writer, CurrentVersion: "3.1.0"}}
+			td := &TemplateData{NMAPRun: run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("CycloneDXFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err != nil {
+				return
+			}
+			var bom CycloneDXBOM
+			if err := json.Unmarshal(writer.data, &bom); err != nil {
+				t.Fatalf("could not unmarshal CycloneDX output: %v", err)
+			}
+			if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != CycloneDXSpecVersion || bom.Version != 1 {
+				t.Errorf("CycloneDXFormatter.Format() wrong document header: %+v", bom)
+			}
+			if !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+				t.Errorf("CycloneDXFormatter.Format() serial number = %s, want urn:uuid", bom.SerialNumber)
+			}
+			if bom.Metadata.Timestamp != "2023-11-14T22:13:20Z" {
+				t.Errorf("CycloneDXFormatter.Format() timestamp = %s", bom.Metadata.Timestamp)
+			}
+			wantTools := []CycloneDXComponent{
+				{Type: "application", Name: "nmap", Version: "7.94"},
+				{Type: "application", Name: "nmap-formatter", Version: "3.1.0"},
+			}
+			if !reflect.DeepEqual(bom.Metadata.Tools.Components, wantTools) {
+				t.Errorf("CycloneDXFormatter.Format() tools = %+v, want %+v", bom.Metadata.Tools.Components, wantTools)
+			}
+			if !reflect.DeepEqual(bom.Components, tt.want) {
+				t.Errorf("CycloneDXFormatter.Format() components = %+v, want %+v", bom.Components, tt.want)
+			}
+		})
+	}
+}
+
+func Test_cycloneDXHostKeys(t *testing.T) {
+	hosts := []Host{
+		{HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}}},
+		{},
+		{},
+		{HostAddress: []HostAddress{{Address: "fe80::1", AddressType: "ipv6"}}},
+		{HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}}},
+	}
+	want := []string{"192.168.1.10", "host1", "host2", "fe80-1", "host4"}
+	if got := cycloneDXHostKeys(hosts); !reflect.DeepEqual(got, want) {
+		t.Errorf("cycloneDXHostKeys() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,237 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CypherFormatter is struct defined for Neo4j Cypher Output use-case
+type CypherFormatter struct {
+	config *Config
+}
+
+// cypherRaw is a Cypher expression that is written to the statement as is, without quoting
+type cypherRaw string
+
+// cypherProperty is a property of a node or relationship, properties keep the order of definition
+type cypherProperty struct {
+	key   string
+	value any
+}
+
+// cypherConstraints make MERGE statements fast and prevent duplicated nodes,
+// services are matched by name, product and version, so they don't have a single key
+var cypherConstraints = []struct {
+	name     string
+	label    string
+	property string
+}{
+	{"nmap_host_address", "Host", "address"},
+	{"nmap_port_id", "Port", "id"},
+	{"nmap_os_name", "OS", "name"},
+	{"nmap_hop_address", "Hop", "address"},
+}
+
+// Format the data to Cypher statements and output it to appropriate io.Writer, all statements
+// are idempotent `MERGE` statements, so loading the same or newer scan updates existing nodes
+func (f *CypherFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "// nmap-formatter %s Cypher export: %s\n", f.config.CurrentVersion, td.NMAPRun.Args)
+	for _, c := range cypherConstraints {
+		fmt.Fprintf(b, "CREATE CONSTRAINT %s IF NOT EXISTS FOR (n:%s) REQUIRE n.%s IS UNIQUE;\n", c.name, c.label, c.property)
+	}
+	seen := cypherRaw(fmt.Sprintf("datetime({epochSeconds: %d})", td.NMAPRun.Start))
+	for i := range td.NMAPRun.Host {
+		host := &td.NMAPRun.Host[i]
+		address := cypherHostAddress(host)
+		if address == "" {
+			continue
+		}
+		b.WriteString("\n")
+		cypherHost(b, host, address, seen)
+		cypherOS(b, host, address)
+		for j := range host.Port {
+			cypherPort(b, &host.Port[j], address)
+		}
+		cypherRoute(b, host, address)
+	}
+	_, err = f.config.Writer.Write([]byte(b.String()))
+	return err
+}
+
+// cypherHostAddress returns the first IP address of the host, MAC address is used
+// only if the host does not have any IP address
+func cypherHostAddress(h *Host) string {
+	for _, a := range h.HostAddress {
+		if a.AddressType != "mac" {
+			return a.Address
+		}
+	}
+	if len(h.HostAddress) > 0 {
+		return h.HostAddress[0].Address
+	}
+	return ""
+}
+
+// cypherHost writes the Host node, `first_seen` is set only once when the node is created
+func cypherHost(b *strings.Builder, h *Host, address string, seen cypherRaw) {
+	hostnames := []string{}
+	for _, hostname := range h.HostNames.HostName {
+		hostnames = append(hostnames, hostname.Name)
+	}
+	properties := []cypherProperty{
+		{"hostnames", hostnames},
+		{"state", h.Status.State},
+		{"reason", h.Status.Reason},
+		{"last_seen", seen},
+	}
+	for _, a := range h.HostAddress {
+		if a.AddressType == "mac" {
+			properties = append(properties, cypherProperty{"mac", a.Address}, cypherProperty{"vendor", a.Vendor})
+		}
+	}
+	if h.Distance.Value != 0 {
+		properties = append(properties, cypherProperty{"distance", h.Distance.Value})
+	}
+	fmt.Fprintf(
+		b,
+		"MERGE (h:Host {address: %s}) ON CREATE SET h.first_seen = %s %s;\n",
+		cypherValue(address),
+		seen,
+		cypherSet("h", properties),
+	)
+}
+
+// cypherOS writes the OS node of the best OS match and `RUNS` relationship, relationship
+// to previously detected OS is removed
+func cypherOS(b *strings.Builder, h *Host, address string) {
+	if len(h.OS.OSMatch) == 0 {
+		return
+	}
+	match := h.OS.OSMatch[0]
+	properties := []cypherProperty{}
+	if class, ok := h.OS.firstOSClass(); ok {
+		properties = append(
+			properties,
+			cypherProperty{"vendor", class.Vendor},
+			cypherProperty{"family", class.OSFamily},
+			cypherProperty{"generation", class.OSGen},
+			cypherProperty{"type", class.Type},
+		)
+	}
+	accuracy, _ := strconv.Atoi(match.Accuracy)
+	fmt.Fprintf(
+		b,
+		"MATCH (h:Host {address: %s}) MERGE (o:OS {name: %s}) %sMERGE (h)-[r:RUNS]->(o) SET r.accuracy = %d "+
+			"WITH h, o OPTIONAL MATCH (h)-[old:RUNS]->(other:OS) WHERE other <> o DELETE old;\n",
+		cypherValue(address),
+		cypherValue(match.Name),
+		cypherSetClause("o", properties),
+		accuracy,
+	)
+}
+
+// cypherPort writes the Port node with `HAS_PORT` relationship and the Service node with `RUNS`
+// relationship, relationship to previously detected service is removed
+func cypherPort(b *strings.Builder, p *Port, address string) {
+	id := fmt.Sprintf("%s/%s/%d", address, p.Protocol, p.PortID)
+	fmt.Fprintf(
+		b,
+		"MATCH (h:Host {address: %s}) MERGE (p:Port {id: %s}) %s MERGE (h)-[:HAS_PORT]->(p);\n",
+		cypherValue(address),
+		cypherValue(id),
+		cypherSet("p", []cypherProperty{
+			{"protocol", p.Protocol},
+			{"number", p.PortID},
+			{"state", p.State.State},
+			{"reason", p.State.Reason},
+		}),
+	)
+	if p.Service.Name == "" {
+		return
+	}
+	cpe := p.Service.CPE
+	if cpe == nil {
+		cpe = []string{}
+	}
+	fmt.Fprintf(
+		b,
+		"MATCH (p:Port {id: %s}) MERGE (s:Service {name: %s, product: %s, version: %s}) %s MERGE (p)-[:RUNS]->(s) "+
+			"WITH p, s OPTIONAL MATCH (p)-[old:RUNS]->(other:Service) WHERE other <> s DELETE old;\n",
+		cypherValue(id),
+		cypherValue(p.Service.Name),
+		cypherValue(p.Service.Product),
+		cypherValue(p.Service.Version),
+		cypherSet("s", []cypherProperty{{"extra_info", p.Service.ExtraInfo}, {"cpe", cpe}}),
+	)
+}
+
+// cypherRoute writes Hop nodes and `ROUTES_VIA` relationships from the host to every hop of
+// the trace, the last hop is the host itself, so it's skipped; previous route is replaced
+func cypherRoute(b *strings.Builder, h *Host, address string) {
+	if len(h.Trace.Hops) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "MATCH (h:Host {address: %s})-[r:ROUTES_VIA]->(:Hop) DELETE r;\n", cypherValue(address))
+	for _, hop := range h.Trace.Hops {
+		if hop.IPAddr == "" || hop.IPAddr == address {
+			continue
+		}
+		fmt.Fprintf(
+			b,
+			"MATCH (h:Host {address: %s}) MERGE (hop:Hop {address: %s}) %s MERGE (h)-[r:ROUTES_VIA]->(hop) %s;\n",
+			cypherValue(address),
+			cypherValue(hop.IPAddr),
+			cypherSet("hop", []cypherProperty{{"host", hop.Host}}),
+			cypherSet("r", []cypherProperty{{"ttl", hop.TTL}, {"rtt", float64(hop.RTT)}}),
+		)
+	}
+}
+
+// cypherSet returns `SET` clause that sets all properties of the variable
+func cypherSet(variable string, properties []cypherProperty) string {
+	assignments := make([]string, 0, len(properties))
+	for _, p := range properties {
+		assignments = append(assignments, fmt.Sprintf("%s.%s = %s", variable, p.key, cypherValue(p.value)))
+	}
+	return "SET " + strings.Join(assignments, ", ")
+}
+
+// cypherSetClause returns `SET` clause followed by a space or empty string if there are no properties
+func cypherSetClause(variable string, properties []cypherProperty) string {
+	if len(properties) == 0 {
+		return ""
+	}
+	return cypherSet(variable, properties) + " "
+}
+
+// cypherValue converts the value to Cypher literal
+func cypherValue(v any) string {
+	switch v := v.(type) {
+	case cypherRaw:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		values := make([]string, 0, len(v))
+		for _, s := range v {
+			values = append(values, cypherValue(s))
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	}
+	return cypherQuote(fmt.Sprint(v))
+}
+
+// cypherQuote returns single-quoted Cypher string literal
+func cypherQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + replacer.Replace(s) + "'"
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *CypherFormatter) defaultTemplateContent() string {
+	return ""
+}
@@ -0,0 +1,107 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCypherFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name       string
+		run        NMAPRun
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Hosts, services and routes",
+			run: NMAPRun{
+				Args:  "nmap -sV -O --traceroute 10.0.5.20",
+				Start: 1700000000,
+				Host: []Host{
+					{
+						HostAddress: []HostAddress{{Address: "10.0.5.20", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac", Vendor: "Dell"}},
+						HostNames:   HostNames{HostName: []HostName{{Name: "o'brien.local"}}},
+						Status:      HostStatus{State: "up", Reason: "echo-reply"},
+						Distance:    Distance{Value: 2},
+						OS: OS{
+							OSMatch: []OSMatch{
+								{
+									Name:     "Microsoft Windows Server 2019",
+									Accuracy: "96",
+									OSClass:  []OSClass{{Vendor: "Microsoft", OSFamily: "Windows", OSGen: "2019", Type: "general purpose"}},
+								},
+							},
+						},
+						Port: []Port{
+							{Protocol: "tcp", PortID: 1433, State: PortState{State: "open", Reason: "syn-ack"}, Service: PortService{Name: "ms-sql-s", Product: "Microsoft SQL Server 2019", Version: "15.00.2000", CPE: []string{"cpe:/a:microsoft:sql_server:2019"}}},
+							{Protocol: "tcp", PortID: 3389, State: PortState{State: "filtered", Reason: "no-response"}},
+						},
+						Trace: Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.52, Host: "gw.local"}, {TTL: 2, IPAddr: "10.0.5.20", RTT: 1.3}}},
+					},
+					{
+						HostAddress: []HostAddress{{Address: "00:1A:2B:3C:4D:5F", AddressType: "mac"}},
+						Status:      HostStatus{State: "up", Reason: "arp-response"},
+					},
+					{
+						Status: HostStatus{State: "down"},
+					},
+				},
+			},
+			wantOutput: `// nmap-formatter 3.0.0 Cypher export: nmap -sV -O --traceroute 10.0.5.20
+CREATE CONSTRAINT nmap_host_address IF NOT EXISTS FOR (n:Host) REQUIRE n.address IS UNIQUE;
+CREATE CONSTRAINT nmap_port_id IF NOT EXISTS FOR (n:Port) REQUIRE n.id IS UNIQUE;
+CREATE CONSTRAINT nmap_os_name IF NOT EXISTS FOR (n:OS) REQUIRE n.name IS UNIQUE;
+CREATE CONSTRAINT nmap_hop_address IF NOT EXISTS FOR (n:Hop) REQUIRE n.address IS UNIQUE;
+
+MERGE (h:Host {address: '10.0.5.20'}) ON CREATE SET h.first_seen = datetime({epochSeconds: 1700000000}) SET h.hostnames = ['o\'brien.local'], h.state = 'up', h.reason = 'echo-reply', h.last_seen = datetime({epochSeconds: 1700000000}), h.mac = '00:1A:2B:3C:4D:5E', h.vendor = 'Dell', h.distance = 2;
+MATCH (h:Host {address: '10.0.5.20'}) MERGE (o:OS {name: 'Microsoft Windows Server 2019'}) SET o.vendor = 'Microsoft', o.family = 'Windows', o.generation = '2019', o.type = 'general purpose' MERGE (h)-[r:RUNS]->(o) SET r.accuracy = 96 WITH h, o OPTIONAL MATCH (h)-[old:RUNS]->(other:OS) WHERE other <> o DELETE old;
+MATCH (h:Host {address: '10.0.5.20'}) MERGE (p:Port {id: '10.0.5.20/tcp/1433'}) SET p.protocol = 'tcp', p.number = 1433, p.state = 'open', p.reason = 'syn-ack' MERGE (h)-[:HAS_PORT]->(p);
+MATCH (p:Port {id: '10.0.5.20/tcp/1433'}) MERGE (s:Service {name: 'ms-sql-s', product: 'Microsoft SQL Server 2019', version: '15.00.2000'}) SET s.extra_info = '', s.cpe = ['cpe:/a:microsoft:sql_server:2019'] MERGE (p)-[:RUNS]->(s) WITH p, s OPTIONAL MATCH (p)-[old:RUNS]->(other:Service) WHERE other <> s DELETE old;
+MATCH (h:Host {address: '10.0.5.20'}) MERGE (p:Port {id: '10.0.5.20/tcp/3389'}) SET p.protocol = 'tcp', p.number = 3389, p.state = 'filtered', p.reason = 'no-response' MERGE (h)-[:HAS_PORT]->(p);
+MATCH (h:Host {address: '10.0.5.20'})-[r:ROUTES_VIA]->(:Hop) DELETE r;
+MATCH (h:Host {address: '10.0.5.20'}) MERGE (hop:Hop {address: '10.0.0.1'}) SET hop.host = 'gw.local' MERGE (h)-[r:ROUTES_VIA]->(hop) SET r.ttl = 1, r.rtt = 0.52;
+
+MERGE (h:Host {address: '00:1A:2B:3C:4D:5F'}) ON CREATE SET h.first_seen = datetime({epochSeconds: 1700000000}) SET h.hostnames = [], h.state = 'up', h.reason = 'arp-response', h.last_seen = datetime({epochSeconds: 1700000000}), h.mac = '00:1A:2B:3C:4D:5F', h.vendor = '';
+`,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &CypherFormatter{config: &Config{Writer: writer, CurrentVersion: "3.0.0"}}
+			td := &TemplateData{NMAPRun: tt.run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("CypherFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.wantOutput != string(writer.data) {
+				t.Errorf("CypherFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func Test_cypherQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Plain", s: "nginx", want: `'nginx'`},
+		{name: "Quote", s: "Jetty's server", want: `'Jetty\'s server'`},
+		{name: "Backslash", s: `C:\web`, want: `'C:\\web'`},
+		{name: "Multiline", s: "line1\n\tline2", want: `'line1\n\tline2'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cypherQuote(tt.s); got != tt.want {
+				t.Errorf("cypherQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,37 +5,102 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash/fnv"
+	"sort"
+	"strings"
 
 	"oss.terrastruct.com/d2/d2format"
 	"oss.terrastruct.com/d2/d2graph"
 	"oss.terrastruct.com/d2/d2layouts/d2dagrelayout"
+	"oss.terrastruct.com/d2/d2layouts/d2elklayout"
 	"oss.terrastruct.com/d2/d2lib"
 	"oss.terrastruct.com/d2/d2oracle"
+	"oss.terrastruct.com/d2/d2renderers/d2svg"
+	"oss.terrastruct.com/d2/d2themes/d2themescatalog"
 	"oss.terrastruct.com/d2/lib/log"
 	"oss.terrastruct.com/d2/lib/textmeasure"
 )
 
+const (
+	// D2DagreLayout is a default layout engine of D2
+	D2DagreLayout = "dagre"
+	// D2ELKLayout is ELK layout engine, which routes edges orthogonally and handles bigger graphs better
+	D2ELKLayout = "elk"
+)
+
 // D2LangFormatter is struct defined for D2 Language Output use-case
 type D2LangFormatter struct {
 	config *Config
 }
 
-// Format the data to D2 Language and output it to a D2 Language file
+// Format the data to D2 Language and output it to a D2 Language file,
+// or render it to SVG image if it's requested in the options
 func (f *D2LangFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.D2LangOptions
+	graph, err := d2Graph(&td.NMAPRun, options, &td.OutputOptions.DotOptions, false)
+	if err != nil {
+		return err
+	}
+	if options.SVG {
+		svg, err := d2SVG(graph, options)
+		if err != nil {
+			return err
+		}
+		_, err = f.config.Writer.Write(svg)
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte(options.configVars() + d2format.Format(graph.AST)))
+	return
+}
+
+// d2CompileOptions returns options to compile D2 script with the chosen layout engine
+func d2CompileOptions(layout string) *d2lib.CompileOptions {
 	ruler, _ := textmeasure.NewRuler()
 	layoutResolver := func(engine string) (d2graph.LayoutGraph, error) {
+		if layout == D2ELKLayout {
+			return d2elklayout.DefaultLayout, nil
+		}
 		return d2dagrelayout.DefaultLayout, nil
 	}
-	compileOpts := &d2lib.CompileOptions{
+	return &d2lib.CompileOptions{
 		LayoutResolver: layoutResolver,
 		Ruler:          ruler,
 	}
+}
 
-	_, graph, _ := d2lib.Compile(log.WithDefault(context.Background()), "nmap", compileOpts, nil)
+// d2Graph builds D2 graph where scanner is connected to the hosts through traceroute hops (edges are labeled with RTT)
+// and hosts are connected to their ports, if linkHosts is set, hosts link to their sections in HTML report (`#0`, `#1`, etc.),
+// port state colors are taken from Dot options
+func d2Graph(n *NMAPRun, options *D2LangOutputOptions, colors *DotOutputOptions, linkHosts bool) (*d2graph.Graph, error) {
+	_, graph, _ := d2lib.Compile(log.WithDefault(context.Background()), "nmap", d2CompileOptions(options.layout()), nil)
+	if options.Direction != "" {
+		graph, _ = d2oracle.Set(graph, nil, "direction", nil, &options.Direction)
+	}
 
-	for i := range td.NMAPRun.Host {
-		host := &td.NMAPRun.Host[i]
-		fnv := fnv.New128()
+	hops := n.AllHops()
+	hopIPs := make([]string, 0, len(hops))
+	for ip := range hops {
+		hopIPs = append(hopIPs, ip)
+	}
+	sort.Strings(hopIPs)
+	for _, ip := range hopIPs {
+		hopID, err := d2ID("hop" + ip)
+		if err != nil {
+			return nil, err
+		}
+		hopLabel := ip
+		if hops[ip].Host != "" {
+			hopLabel = fmt.Sprintf("%s\n(%s)", ip, hops[ip].Host)
+		}
+		graph, _, _ = d2oracle.Create(graph, nil, hopID)
+		graph, _ = d2oracle.Set(graph, nil, hopID+".label", nil, &hopLabel)
+		shape := "circle"
+		graph, _ = d2oracle.Set(graph, nil, hopID+".shape", nil, &shape)
+	}
+
+	// Hops are shared between the routes, so each edge is created only once
+	edges := map[string]bool{}
+	for i := range n.Host {
+		host := &n.Host[i]
 
 		address := host.JoinedAddresses("/")
 		hostnames := host.JoinedHostNames("/")
@@ -43,18 +108,45 @@ func (f *D2LangFormatter) Format(td *TemplateData, templateContent string) (err
 		if hostnames != "" {
 			hostLabel = fmt.Sprintf("%s\n(%s)", address, hostnames)
 		}
-		_, err := fnv.Write([]byte(address))
+		hostID, err := d2ID(address)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if options.GroupBySubnet {
+			if subnet := hostSubnet(host, subnetPrefix(options.SubnetPrefix)); subnet != "" {
+				subnetID, err := d2ID("subnet" + subnet)
+				if err != nil {
+					return nil, err
+				}
+				graph, _ = d2oracle.Set(graph, nil, subnetID+".label", nil, &subnet)
+				hostID = subnetID + "." + hostID
+			}
 		}
 
-		hostID := hex.EncodeToString(fnv.Sum(nil))
 		graph, _, _ = d2oracle.Create(graph, nil, hostID)
 		graph, _ = d2oracle.Set(graph, nil, hostID+".label", nil, &hostLabel)
-		graph, _ = d2oracle.Set(graph, nil, "nmap -> "+hostID, nil, nil)
+		if linkHosts {
+			link := fmt.Sprintf("#%d", i)
+			graph, _ = d2oracle.Set(graph, nil, hostID+".link", nil, &link)
+		}
+
+		route, err := d2Route(host.Trace.Hops, hostID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range route {
+			if edges[e.key] {
+				continue
+			}
+			edges[e.key] = true
+			graph, _ = d2oracle.Set(graph, nil, e.key, nil, e.label)
+		}
 
 		for j := range host.Port {
 			port := &host.Port[j]
+			if options.skipPort(port) {
+				continue
+			}
 			portID := fmt.Sprintf("%s-port%d", hostID, port.PortID)
 			graph, _, _ = d2oracle.Create(graph, nil, portID)
 			portLabel := fmt.Sprintf("%d/%s\n%s\n%s", port.PortID, port.Protocol, port.State.State, port.Service.Name)
@@ -63,11 +155,122 @@ func (f *D2LangFormatter) Format(td *TemplateData, templateContent string) (err
 			graph, _ = d2oracle.Set(graph, nil, fmt.Sprintf("%s.shape", portID), nil, &shape)
 			width := "25"
 			graph, _ = d2oracle.Set(graph, nil, fmt.Sprintf("%s.width", portID), nil, &width)
+			// Port state colors are the same as in the Dot (Graphviz) output
+			color := colors.portStateColor(port)
+			graph, _ = d2oracle.Set(graph, nil, fmt.Sprintf("%s.style.stroke", portID), nil, &color)
+			graph, _ = d2oracle.Set(graph, nil, fmt.Sprintf("%s.style.font-color", portID), nil, &color)
 			graph, _ = d2oracle.Set(graph, nil, hostID+" -> "+portID, nil, nil)
 		}
 	}
-	_, err = f.config.Writer.Write([]byte(d2format.Format(graph.AST)))
-	return
+	return graph, nil
+}
+
+// d2Edge is an edge key (`a -> b`) with an optional label
+type d2Edge struct {
+	key   string
+	label *string
+}
+
+// d2Route returns edges from the scanner through traceroute hops to the host, edges are labeled
+// with RTT of the hop they lead to, the last hop has the same IP as the host, so it leads to the host itself
+func d2Route(hops []Hop, hostID string) ([]d2Edge, error) {
+	route := []d2Edge{}
+	previous := "nmap"
+	for i := range hops {
+		target := hostID
+		if i != len(hops)-1 {
+			hopID, err := d2ID("hop" + hops[i].IPAddr)
+			if err != nil {
+				return nil, err
+			}
+			target = hopID
+		}
+		var label *string
+		if hops[i].RTT > 0 {
+			rtt := fmt.Sprintf("%vms", float64(hops[i].RTT))
+			label = &rtt
+		}
+		route = append(route, d2Edge{key: previous + " -> " + target, label: label})
+		previous = target
+	}
+	if len(route) == 0 {
+		route = append(route, d2Edge{key: "nmap -> " + hostID})
+	}
+	return route, nil
+}
+
+// d2ID returns D2 identifier based on the hash of the value, so addresses with dots and colons
+// are not treated as nested keys
+func d2ID(value string) (string, error) {
+	fnv := fnv.New128()
+	_, err := fnv.Write([]byte(value))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(fnv.Sum(nil)), nil
+}
+
+// d2SVG renders D2 graph to SVG image in-process with the layout engine and the theme from the options
+func d2SVG(graph *d2graph.Graph, options *D2LangOutputOptions) ([]byte, error) {
+	pad := int64(d2svg.DEFAULT_PADDING)
+	themeID := options.ThemeID
+	renderOpts := &d2svg.RenderOpts{Pad: &pad, ThemeID: &themeID}
+	diagram, _, err := d2lib.Compile(log.WithDefault(context.Background()), d2format.Format(graph.AST), d2CompileOptions(options.layout()), renderOpts)
+	if err != nil {
+		return nil, err
+	}
+	return d2svg.Render(diagram, renderOpts)
+}
+
+// layout returns layout engine, D2DagreLayout is used by default
+func (o *D2LangOutputOptions) layout() string {
+	if o.Layout == "" {
+		return D2DagreLayout
+	}
+	return o.Layout
+}
+
+// skipPort returns whether port is left out of the diagram because of its state
+func (o *D2LangOutputOptions) skipPort(p *Port) bool {
+	return (o.SkipClosedPorts && p.State.State == "closed") ||
+		(o.SkipFilteredPorts && strings.Contains(p.State.State, "filtered"))
+}
+
+// configVars returns D2 configuration block with the layout engine and the theme,
+// so `d2` CLI renders D2 language file the same way, nothing is returned for the defaults
+func (o *D2LangOutputOptions) configVars() string {
+	var config []string
+	if o.layout() != D2DagreLayout {
+		config = append(config, fmt.Sprintf("    layout-engine: %s\n", o.layout()))
+	}
+	if o.ThemeID != 0 {
+		config = append(config, fmt.Sprintf("    theme-id: %d\n", o.ThemeID))
+	}
+	if len(config) == 0 {
+		return ""
+	}
+	return "vars: {\n  d2-config: {\n" + strings.Join(config, "") + "  }\n}\n"
+}
+
+// Validate checks whether layout engine, theme, direction and subnet prefix are correct
+func (o *D2LangOutputOptions) Validate() error {
+	switch o.layout() {
+	case D2DagreLayout, D2ELKLayout:
+	default:
+		return fmt.Errorf("unknown D2 layout engine: %s, please choose dagre/elk", o.Layout)
+	}
+	if d2themescatalog.Find(o.ThemeID).Name == "" {
+		return fmt.Errorf("unknown D2 theme: %d, available themes:\n%s", o.ThemeID, d2themescatalog.CLIString())
+	}
+	switch o.Direction {
+	case "", "up", "down", "left", "right":
+	default:
+		return fmt.Errorf("unknown D2 direction: %s, please choose up/down/left/right", o.Direction)
+	}
+	if err := validateSubnetPrefix(o.SubnetPrefix); err != nil {
+		return fmt.Errorf("wrong D2 subnet prefix: %v", err)
+	}
+	return nil
 }
 
 // defaultTemplateContent does not return anything in this case
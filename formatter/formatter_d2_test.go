@@ -1,6 +1,11 @@
 package formatter
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"oss.terrastruct.com/d2/d2format"
+)
 
 type d2MockedWriter struct {
 	data []byte
@@ -150,3 +155,198 @@ func TestD2LangFormatter_Format(t *testing.T) {
 		})
 	}
 }
+
+func Test_d2SVG(t *testing.T) {
+	n := &NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.10.10.1", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "<b>web</b>&co"}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 22, State: PortState{State: "open"}, Service: PortService{Name: "ssh"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.10.10.2", AddressType: "ipv4"}},
+			},
+		},
+	}
+	for _, options := range []D2LangOutputOptions{{}, {ThemeID: 200, Layout: D2ELKLayout, Direction: "right"}} {
+		graph, err := d2Graph(n, &options, &DotOutputOptions{}, true)
+		if err != nil {
+			t.Fatalf("d2Graph() error = %v", err)
+		}
+		svg, err := d2SVG(graph, &options)
+		if err != nil {
+			t.Fatalf("d2SVG() error = %v", err)
+		}
+		output := string(svg)
+		for _, want := range []string{"<svg", `<a href="#0"`, `<a href="#1"`, "&lt;b&gt;web&lt;/b&gt;&amp;co", "22/tcp"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("d2SVG() output does not contain %q", want)
+			}
+		}
+		if strings.Contains(output, "<b>web") {
+			t.Errorf("d2SVG() labels should be escaped")
+		}
+	}
+}
+
+func TestD2LangFormatter_Format_options(t *testing.T) {
+	n := NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.10.10.1", AddressType: "ipv4"}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 22, State: PortState{State: "open"}, Service: PortService{Name: "ssh"}},
+					{Protocol: "tcp", PortID: 23, State: PortState{State: "closed"}, Service: PortService{Name: "telnet"}},
+					{Protocol: "tcp", PortID: 25, State: PortState{State: "filtered"}, Service: PortService{Name: "smtp"}},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "open|filtered"}, Service: PortService{Name: "domain"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      D2LangOutputOptions
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name:         "Default options",
+			wantContains: []string{"22/tcp", "23/tcp", "25/tcp", "53/udp"},
+			wantMissing:  []string{"vars", "direction"},
+		},
+		{
+			name:         "Layout, theme and direction",
+			options:      D2LangOutputOptions{Layout: D2ELKLayout, ThemeID: 200, Direction: "right"},
+			wantContains: []string{"vars: {\n  d2-config: {\n    layout-engine: elk\n    theme-id: 200\n  }\n}\n", "direction: right"},
+		},
+		{
+			name:         "Skip closed ports",
+			options:      D2LangOutputOptions{SkipClosedPorts: true},
+			wantContains: []string{"22/tcp", "25/tcp", "53/udp"},
+			wantMissing:  []string{"23/tcp"},
+		},
+		{
+			name:         "Skip filtered ports",
+			options:      D2LangOutputOptions{SkipFilteredPorts: true},
+			wantContains: []string{"22/tcp", "23/tcp"},
+			wantMissing:  []string{"25/tcp", "53/udp"},
+		},
+		{
+			name:         "SVG",
+			options:      D2LangOutputOptions{SVG: true},
+			wantContains: []string{"<svg", "22/tcp"},
+			wantMissing:  []string{"->"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &d2MockedWriter{}
+			f := &D2LangFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: n, OutputOptions: OutputOptions{D2LangOptions: tt.options}}
+			if err := f.Format(td, f.defaultTemplateContent()); err != nil {
+				t.Fatalf("D2LangFormatter.Format() error = %v", err)
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("D2LangFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("D2LangFormatter.Format() output should not contain %q", missing)
+				}
+			}
+		})
+	}
+}
+
+func TestD2LangOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options D2LangOutputOptions
+		wantErr bool
+	}{
+		{name: "Default", options: D2LangOutputOptions{}},
+		{name: "Valid options", options: D2LangOutputOptions{Layout: D2ELKLayout, ThemeID: 200, Direction: "left"}},
+		{name: "Wrong layout", options: D2LangOutputOptions{Layout: "tala"}, wantErr: true},
+		{name: "Wrong theme", options: D2LangOutputOptions{ThemeID: 12345}, wantErr: true},
+		{name: "Wrong direction", options: D2LangOutputOptions{Direction: "diagonal"}, wantErr: true},
+		{name: "Wrong subnet prefix", options: D2LangOutputOptions{SubnetPrefix: 33}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("D2LangOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_d2Graph_traceAndSubnets(t *testing.T) {
+	n := &NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}},
+				Port:        []Port{{Protocol: "tcp", PortID: 23, State: PortState{State: "closed"}, Service: PortService{Name: "telnet"}}},
+				Trace: Trace{Hops: []Hop{
+					{TTL: 1, IPAddr: "192.168.1.1", RTT: 0.5, Host: "gateway"},
+					{TTL: 2, IPAddr: "10.0.0.1", RTT: 1.25},
+				}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.2", AddressType: "ipv4"}},
+				Trace: Trace{Hops: []Hop{
+					{TTL: 1, IPAddr: "192.168.1.1", RTT: 0.5, Host: "gateway"},
+					{TTL: 2, IPAddr: "10.0.0.2", RTT: 1.5},
+				}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.1", AddressType: "ipv4"}},
+			},
+		},
+	}
+	hopID, _ := d2ID("hop192.168.1.1")
+	subnetID, _ := d2ID("subnet10.0.0.0/24")
+	host1ID, _ := d2ID("10.0.0.1")
+	otherSubnetID, _ := d2ID("subnet10.0.1.0/24")
+	host2ID, _ := d2ID("10.0.1.1")
+
+	graph, err := d2Graph(n, &D2LangOutputOptions{GroupBySubnet: true}, &DotOutputOptions{}, false)
+	if err != nil {
+		t.Fatalf("d2Graph() error = %v", err)
+	}
+	output := d2format.Format(graph.AST)
+	for _, want := range []string{
+		hopID + `: "192.168.1.1\n(gateway)"`,
+		"nmap -> " + hopID + ": 0.5ms\n",
+		hopID + " -> " + subnetID + "." + host1ID + ": 1.25ms\n",
+		subnetID + ".label: 10.0.0.0/24",
+		"nmap -> " + otherSubnetID + "." + host2ID + "\n",
+		`style.stroke: "` + DotClosedPortColor + `"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("d2Graph() output does not contain %q, output = \n%s", want, output)
+		}
+	}
+	if strings.Count(output, "nmap -> "+hopID) != 1 {
+		t.Errorf("d2Graph() shared hop edge should be created once, output = \n%s", output)
+	}
+
+	graph, err = d2Graph(n, &D2LangOutputOptions{GroupBySubnet: true, SubnetPrefix: 16}, &DotOutputOptions{ClosedPortColor: "red"}, true)
+	if err != nil {
+		t.Fatalf("d2Graph() error = %v", err)
+	}
+	wideSubnetID, _ := d2ID("subnet10.0.0.0/16")
+	output = d2format.Format(graph.AST)
+	if !strings.Contains(output, wideSubnetID+"."+host2ID) {
+		t.Errorf("d2Graph() hosts should be grouped by /16 subnet, output = \n%s", output)
+	}
+	if !strings.Contains(output, "style.stroke: red") {
+		t.Errorf("d2Graph() closed port should use the color of the options, output = \n%s", output)
+	}
+	if _, err = d2SVG(graph, &D2LangOutputOptions{}); err != nil {
+		t.Errorf("d2SVG() error = %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	_ "embed"
 	"fmt"
 	"hash/fnv"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -38,30 +40,43 @@ const (
 	DotLayout = "dot"
 )
 
-// DotDefaultOptions is a config map that is used in Graphviz template
+// DotDefaultOptions is a config map with default values of `.Constants` used in Graphviz template,
+// values which are set in DotOutputOptions take precedence over them
 var DotDefaultOptions = map[string]string{
 	"default_font":  DotFontStyle,
 	"layout":        DotLayout,
 	"color_default": DotDefaultColor,
 }
 
+// DotLayouts is a list of Graphviz layout engines that can be used
+var DotLayouts = []string{"dot", "neato", "fdp", "sfdp", "circo", "twopi", "osage", "patchwork"}
+
 // DotTemplateData is a custom TemplateData struct that is used by DotFormatter
 type DotTemplateData struct {
 	NMAPRun   *NMAPRun
 	Constants map[string]string
+	Options   *DotOutputOptions
+}
+
+// dotCluster is a group of hosts in the same subnet, hosts without IP address have empty subnet
+type dotCluster struct {
+	Subnet string
+	Hosts  []int
 }
 
 // Format the data and output it to appropriate io.Writer
 func (f *DotFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.DotOptions
 	tmpl := template.New("dot")
-	f.defineTemplateFunctions(tmpl)
+	f.defineTemplateFunctions(tmpl, options)
 	tmpl, err = tmpl.Parse(templateContent)
 	if err != nil {
 		return
 	}
 	dotTemplateData := DotTemplateData{
 		NMAPRun:   &td.NMAPRun,
-		Constants: DotDefaultOptions,
+		Constants: options.constants(),
+		Options:   options,
 	}
 	return tmpl.Execute(f.config.Writer, dotTemplateData)
 }
@@ -71,15 +86,18 @@ func (f *DotFormatter) defaultTemplateContent() string {
 	return DotTemplate
 }
 
-// defineTemplateFunctions defines all template functions that are used in dot templates
-func (f *DotFormatter) defineTemplateFunctions(tmpl *template.Template) {
+// defineTemplateFunctions defines all template functions that are used in dot templates,
+// port colors, hidden ports and subnet clusters depend on the options
+func (f *DotFormatter) defineTemplateFunctions(tmpl *template.Template, options *DotOutputOptions) {
 	tmpl.Funcs(
 		template.FuncMap{
 			"clean_ip":         cleanIP,
 			"dot_id":           dotID,
 			"dot_quote":        dotQuote,
 			"port_node_id":     portNodeID,
-			"port_state_color": portStateColor,
+			"port_state_color": options.portStateColor,
+			"skip_port":        options.skipPort,
+			"subnet_clusters":  options.subnetClusters,
 			"hop_list":         hopList,
 		},
 	)
@@ -118,19 +136,6 @@ func dotSafeIdentifier(v string) string {
 	return fmt.Sprintf("x%x", h.Sum64())
 }
 
-// portStateColor returns hexademical color value for state port
-func portStateColor(port *Port) string {
-	switch port.State.State {
-	case "open":
-		return DotOpenPortColor
-	case "filtered":
-		return DotFilteredPortColor
-	case "closed":
-		return DotClosedPortColor
-	}
-	return DotDefaultColor
-}
-
 // hopList function returns a map with a list of hops where very first hop is `startHop` (scanner itself)
 func hopList(hops []Hop, startHop string, endHopName string, endHopKey int) map[string]string {
 	hopList := map[string]string{}
@@ -154,3 +159,75 @@ func hopList(hops []Hop, startHop string, endHopName string, endHopKey int) map[
 	}
 	return hopList
 }
+
+// constants returns DotDefaultOptions overridden by the options for the templates which use `.Constants`
+func (o *DotOutputOptions) constants() map[string]string {
+	return map[string]string{
+		"default_font":  dotOption(o.Font, DotDefaultOptions["default_font"]),
+		"layout":        dotOption(o.Layout, DotDefaultOptions["layout"]),
+		"color_default": dotOption(o.DefaultColor, DotDefaultOptions["color_default"]),
+	}
+}
+
+// portStateColor returns color of the port state, default colors are used if they are not set in the options
+func (o *DotOutputOptions) portStateColor(port *Port) string {
+	switch port.State.State {
+	case "open":
+		return dotOption(o.OpenPortColor, DotOpenPortColor)
+	case "filtered":
+		return dotOption(o.FilteredPortColor, DotFilteredPortColor)
+	case "closed":
+		return dotOption(o.ClosedPortColor, DotClosedPortColor)
+	}
+	return dotOption(o.DefaultColor, DotDefaultOptions["color_default"])
+}
+
+// skipPort returns whether port is hidden from the graph
+func (o *DotOutputOptions) skipPort(port *Port) bool {
+	return o.HideClosedPorts && port.State.State == "closed"
+}
+
+// subnetClusters groups hosts by subnet if it's enabled in the options, otherwise all hosts are returned
+// in a single group without subnet, so they are drawn outside of clusters
+func (o *DotOutputOptions) subnetClusters(hosts []Host) []dotCluster {
+	if !o.GroupBySubnet {
+		all := make([]int, len(hosts))
+		for i := range hosts {
+			all[i] = i
+		}
+		return []dotCluster{{Hosts: all}}
+	}
+	groups := hostsBySubnet(hosts, subnetPrefix(o.SubnetPrefix))
+	clusters := make([]dotCluster, 0, len(groups))
+	for subnet, indexes := range groups {
+		clusters = append(clusters, dotCluster{Subnet: subnet, Hosts: indexes})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Subnet < clusters[j].Subnet
+	})
+	return clusters
+}
+
+// Validate checks whether layout engine, rank direction and subnet prefix are correct
+func (o *DotOutputOptions) Validate() error {
+	if o.Layout != "" && !slices.Contains(DotLayouts, o.Layout) {
+		return fmt.Errorf("unknown dot layout engine: %s, please choose %s", o.Layout, strings.Join(DotLayouts, "/"))
+	}
+	switch o.RankDir {
+	case "", "TB", "LR", "BT", "RL":
+	default:
+		return fmt.Errorf("unknown dot rank direction: %s, please choose TB/LR/BT/RL", o.RankDir)
+	}
+	if err := validateSubnetPrefix(o.SubnetPrefix); err != nil {
+		return fmt.Errorf("wrong dot subnet prefix: %v", err)
+	}
+	return nil
+}
+
+// dotOption returns value of the option or the default value if it's not set
+func dotOption(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -65,14 +65,15 @@ func Test_hopList(t *testing.T) {
 	}
 }
 
-func Test_portStateColor(t *testing.T) {
+func TestDotOutputOptions_portStateColor(t *testing.T) {
 	type args struct {
 		port *Port
 	}
 	tests := []struct {
-		name string
-		args args
-		want string
+		name    string
+		options DotOutputOptions
+		args    args
+		want    string
 	}{
 		{
 			name: "Default color",
@@ -118,11 +119,35 @@ func Test_portStateColor(t *testing.T) {
 			},
 			want: "#DC143C",
 		},
+		{
+			name:    "Open port color from the options",
+			options: DotOutputOptions{OpenPortColor: "green"},
+			args: args{
+				port: &Port{
+					State: PortState{
+						State: "open",
+					},
+				},
+			},
+			want: "green",
+		},
+		{
+			name:    "Default color from the options",
+			options: DotOutputOptions{DefaultColor: "black"},
+			args: args{
+				port: &Port{
+					State: PortState{
+						State: "unknown",
+					},
+				},
+			},
+			want: "black",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := portStateColor(tt.args.port); got != tt.want {
-				t.Errorf("portStateColor() = %v, want %v", got, tt.want)
+			if got := tt.options.portStateColor(tt.args.port); got != tt.want {
+				t.Errorf("DotOutputOptions.portStateColor() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -354,3 +379,149 @@ func TestDotFormatter_Format(t *testing.T) {
 		})
 	}
 }
+
+func TestDotFormatter_Format_options(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 22, State: PortState{State: "open"}},
+					{Protocol: "tcp", PortID: 23, State: PortState{State: "closed"}},
+				},
+				Trace: Trace{Hops: []Hop{{IPAddr: "192.168.1.1"}, {IPAddr: "10.0.0.1"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.1", AddressType: "ipv4"}},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      DotOutputOptions
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "Default options",
+			wantContains: []string{
+				`fontname="monospace"`,
+				`layout="dot"`,
+				`"hop19216811" -> "srv0"`,
+				`"srv0_port_tcp_23" [label="tcp/23 (closed)"`,
+				`color="#228B22"`,
+			},
+			wantMissing: []string{"rankdir", "subgraph"},
+		},
+		{
+			name: "Layout, direction, font and colors",
+			options: DotOutputOptions{
+				Layout:          "sfdp",
+				RankDir:         "LR",
+				Font:            "Helvetica",
+				OpenPortColor:   "green",
+				ClosedPortColor: "red",
+			},
+			wantContains: []string{`fontname="Helvetica"`, `layout="sfdp"`, `rankdir="LR"`, `color="green"`, `color="red"`},
+		},
+		{
+			name:         "Hide closed ports",
+			options:      DotOutputOptions{HideClosedPorts: true},
+			wantContains: []string{`"srv0_port_tcp_22"`},
+			wantMissing:  []string{`"srv0_port_tcp_23"`},
+		},
+		{
+			name:    "Group by subnet",
+			options: DotOutputOptions{GroupBySubnet: true},
+			wantContains: []string{
+				"subgraph \"cluster_0\" {\n    label=\"10.0.0.0/24\";\n    \"srv0\"",
+				"subgraph \"cluster_1\" {\n    label=\"10.0.1.0/24\";\n    \"srv1\"",
+				// Route edges are written outside of clusters, otherwise scanner and hops become their members
+				"    }\n    \"hop19216811\" -> \"srv0\"",
+			},
+		},
+		{
+			name:         "Skip traceroute",
+			options:      DotOutputOptions{SkipTraceroute: true},
+			wantContains: []string{`"scanner" -> "srv0"`, `"scanner" -> "srv1"`},
+			wantMissing:  []string{"hop19216811"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &dotMockedWriter{}
+			f := &DotFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: run, OutputOptions: OutputOptions{DotOptions: tt.options}}
+			if err := f.Format(td, f.defaultTemplateContent()); err != nil {
+				t.Fatalf("DotFormatter.Format() error = %v", err)
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("DotFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("DotFormatter.Format() output should not contain %q", missing)
+				}
+			}
+			// Template actions should not leave lines with only whitespace
+			if strings.Contains(output, "\n    \n") {
+				t.Errorf("DotFormatter.Format() output contains blank lines, output = \n%s", output)
+			}
+		})
+	}
+}
+
+func TestDotOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options DotOutputOptions
+		wantErr bool
+	}{
+		{name: "Default", options: DotOutputOptions{}},
+		{name: "Valid options", options: DotOutputOptions{Layout: "neato", RankDir: "BT", SubnetPrefix: 16}},
+		{name: "Wrong layout", options: DotOutputOptions{Layout: "tree"}, wantErr: true},
+		{name: "Wrong rank direction", options: DotOutputOptions{RankDir: "up"}, wantErr: true},
+		{name: "Wrong subnet prefix", options: DotOutputOptions{SubnetPrefix: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("DotOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDotFormatter_Format_subnetClusters(t *testing.T) {
+	hosts := []Host{
+		{HostAddress: []HostAddress{{Address: "1.0.10.1", AddressType: "ipv4"}}},
+		{HostAddress: []HostAddress{{Address: "10.1.0.1", AddressType: "ipv4"}}},
+		{HostAddress: []HostAddress{{Address: "AA:BB:CC:DD:EE:FF", AddressType: "mac"}}},
+	}
+	writer := &dotMockedWriter{}
+	f := &DotFormatter{config: &Config{Writer: writer}}
+	td := &TemplateData{
+		NMAPRun:       NMAPRun{Host: hosts},
+		OutputOptions: OutputOptions{DotOptions: DotOutputOptions{GroupBySubnet: true}},
+	}
+	if err := f.Format(td, f.defaultTemplateContent()); err != nil {
+		t.Fatalf("DotFormatter.Format() error = %v", err)
+	}
+	output := string(writer.data)
+	// Subnets which differ only in punctuation have separate clusters
+	for _, want := range []string{
+		"subgraph \"cluster_1\" {\n    label=\"1.0.10.0/24\";",
+		"subgraph \"cluster_2\" {\n    label=\"10.1.0.0/24\";",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("DotFormatter.Format() output does not contain %q, output = \n%s", want, output)
+		}
+	}
+	if strings.Count(output, "subgraph") != 2 {
+		t.Errorf("DotFormatter.Format() host without IP address should not be in a cluster, output = \n%s", output)
+	}
+}
@@ -0,0 +1,260 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// ECSDefaultIndex is a default Elasticsearch/OpenSearch index name used in bulk actions
+	ECSDefaultIndex = "nmap-formatter"
+	// ECSDefaultBatchSize is a default amount of documents sent in one bulk request
+	ECSDefaultBatchSize = 500
+	// ECSDefaultRetries is a default amount of retries of failed bulk request
+	ECSDefaultRetries = 3
+	// ECSVersion is a version of Elastic Common Schema that documents conform to
+	ECSVersion = "8.11.0"
+)
+
+// ECSFormatter is struct defined for Elastic Common Schema (ECS) Output use-case. Output is
+// NDJSON ready to be sent to `_bulk` API, every port is a separate document
+type ECSFormatter struct {
+	config *Config
+}
+
+// ECSDocument is a document that describes one scanned port using ECS fields,
+// nmap specific information that has no ECS field is located in `nmap` object
+type ECSDocument struct {
+	Timestamp   string         `json:"@timestamp"`
+	ECS         ECSVersionInfo `json:"ecs"`
+	Event       ECSEvent       `json:"event"`
+	Observer    ECSObserver    `json:"observer"`
+	Host        ECSHost        `json:"host"`
+	Destination ECSDestination `json:"destination"`
+	Network     ECSNetwork     `json:"network"`
+	Service     ECSService     `json:"service"`
+	NMAP        ECSNMAP        `json:"nmap"`
+}
+
+// ECSVersionInfo contains the version of ECS (`ecs.version`)
+type ECSVersionInfo struct {
+	Version string `json:"version"`
+}
+
+// ECSEvent contains `event.*` fields
+type ECSEvent struct {
+	Kind     string   `json:"kind"`
+	Category []string `json:"category"`
+	Type     []string `json:"type"`
+	Module   string   `json:"module"`
+	Dataset  string   `json:"dataset"`
+	Start    string   `json:"start,omitempty"`
+	End      string   `json:"end,omitempty"`
+	Duration int64    `json:"duration,omitempty"`
+}
+
+// ECSObserver describes the scanner (`observer.*` fields)
+type ECSObserver struct {
+	Type    string `json:"type"`
+	Product string `json:"product"`
+	Version string `json:"version,omitempty"`
+}
+
+// ECSHost contains `host.*` fields of the scanned host
+type ECSHost struct {
+	IP       []string `json:"ip,omitempty"`
+	MAC      []string `json:"mac,omitempty"`
+	Hostname string   `json:"hostname,omitempty"`
+	OS       *ECSOS   `json:"os,omitempty"`
+}
+
+// ECSOS contains `host.os.*` fields
+type ECSOS struct {
+	Name   string `json:"name,omitempty"`
+	Family string `json:"family,omitempty"`
+}
+
+// ECSDestination contains `destination.*` fields (scanned address and port)
+type ECSDestination struct {
+	IP   string `json:"ip,omitempty"`
+	Port int    `json:"port"`
+}
+
+// ECSNetwork contains `network.*` fields
+type ECSNetwork struct {
+	Transport string `json:"transport"`
+	Type      string `json:"type,omitempty"`
+}
+
+// ECSService contains `service.*` fields
+type ECSService struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	State   string `json:"state"`
+}
+
+// ECSNMAP contains nmap specific fields
+type ECSNMAP struct {
+	Args        string   `json:"args,omitempty"`
+	HostState   string   `json:"host_state,omitempty"`
+	StateReason string   `json:"state_reason,omitempty"`
+	Product     string   `json:"product,omitempty"`
+	ExtraInfo   string   `json:"extra_info,omitempty"`
+	CPE         []string `json:"cpe,omitempty"`
+}
+
+// ecsBulkAction is an action line that precedes every document in `_bulk` request
+type ecsBulkAction struct {
+	Index struct {
+		Index string `json:"_index"`
+		ID    string `json:"_id"`
+	} `json:"index"`
+}
+
+// Format the data to ECS bulk NDJSON and output it to appropriate io.Writer,
+// or send it in batches to HTTP endpoint if it's provided
+func (f *ECSFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.ECSOptions
+	lines, err := ecsBulkLines(&td.NMAPRun, options.index())
+	if err != nil {
+		return err
+	}
+	if options.URL != "" {
+		return newECSHTTPSink(options).Send(lines)
+	}
+	for _, line := range lines {
+		_, err = f.config.Writer.Write(line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ecsBulkLines returns bulk action & document pairs, each line ends with a new line character.
+// Document ID is deterministic, so indexing the same scan again overwrites existing documents,
+// hosts without IP address are identified by their position in the scan
+func ecsBulkLines(n *NMAPRun, index string) ([][]byte, error) {
+	var lines [][]byte
+	for i := range n.Host {
+		host := &n.Host[i]
+		for j := range host.Port {
+			doc := ecsDocument(n, host, &host.Port[j])
+			action := ecsBulkAction{}
+			action.Index.Index = index
+			hostID := doc.Destination.IP
+			if hostID == "" {
+				hostID = fmt.Sprintf("host%d", i)
+			}
+			action.Index.ID = fmt.Sprintf("%d-%s-%s-%d", n.Start, hostID, doc.Network.Transport, doc.Destination.Port)
+			for _, v := range []interface{}{action, doc} {
+				line := &bytes.Buffer{}
+				if err := json.NewEncoder(line).Encode(v); err != nil {
+					return nil, err
+				}
+				lines = append(lines, line.Bytes())
+			}
+		}
+	}
+	return lines, nil
+}
+
+// ecsDocument converts a port of the host to ECS document
+func ecsDocument(n *NMAPRun, h *Host, p *Port) ECSDocument {
+	doc := ECSDocument{
+		ECS: ECSVersionInfo{Version: ECSVersion},
+		Event: ECSEvent{
+			Kind:     "event",
+			Category: []string{"network"},
+			Type:     []string{"info"},
+			Module:   "nmap",
+			Dataset:  "nmap.port",
+		},
+		Observer:    ECSObserver{Type: "scanner", Product: n.Scanner, Version: n.Version},
+		Destination: ECSDestination{Port: p.PortID},
+		Network:     ECSNetwork{Transport: p.Protocol},
+		Service:     ECSService{Name: p.Service.Name, Version: p.Service.Version, State: p.State.State},
+		NMAP: ECSNMAP{
+			Args:        n.Args,
+			HostState:   h.Status.State,
+			StateReason: p.State.Reason,
+			Product:     p.Service.Product,
+			ExtraInfo:   p.Service.ExtraInfo,
+			CPE:         p.Service.CPE,
+		},
+	}
+
+	start := h.StartTime
+	if start == 0 {
+		start = n.Start
+	}
+	doc.Timestamp = ecsTime(start)
+	if h.StartTime != 0 && h.EndTime != 0 {
+		doc.Event.Start = ecsTime(h.StartTime)
+		doc.Event.End = ecsTime(h.EndTime)
+		// Duration is in nanoseconds
+		doc.Event.Duration = int64(h.EndTime-h.StartTime) * int64(time.Second)
+	}
+
+	for _, a := range h.HostAddress {
+		switch a.AddressType {
+		case "mac":
+			doc.Host.MAC = append(doc.Host.MAC, a.Address)
+		case "ipv4", "ipv6":
+			doc.Host.IP = append(doc.Host.IP, a.Address)
+			if doc.Destination.IP == "" {
+				doc.Destination.IP = a.Address
+				doc.Network.Type = a.AddressType
+			}
+		}
+	}
+	if len(h.HostNames.HostName) > 0 {
+		doc.Host.Hostname = h.HostNames.HostName[0].Name
+	}
+	class, hasClass := h.OS.firstOSClass()
+	if len(h.OS.OSMatch) > 0 || hasClass {
+		doc.Host.OS = &ECSOS{Family: class.OSFamily}
+		if len(h.OS.OSMatch) > 0 {
+			doc.Host.OS.Name = h.OS.OSMatch[0].Name
+		}
+	}
+	return doc
+}
+
+// ecsTime converts unix timestamp to ECS date
+func ecsTime(t int) string {
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
+
+// index returns Elasticsearch index name used in bulk actions
+func (o *ECSOutputOptions) index() string {
+	if o.Index == "" {
+		return ECSDefaultIndex
+	}
+	return o.Index
+}
+
+// Validate checks whether HTTP endpoint, batch size and retries are correct
+func (o *ECSOutputOptions) Validate() error {
+	if o.URL != "" {
+		u, err := url.Parse(o.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("ecs url should be a valid http(s) url: %s", o.URL)
+		}
+	}
+	if o.BatchSize < 0 {
+		return fmt.Errorf("ecs batch size should not be negative: %d", o.BatchSize)
+	}
+	if o.Retries < 0 {
+		return fmt.Errorf("ecs retries should not be negative: %d", o.Retries)
+	}
+	return nil
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *ECSFormatter) defaultTemplateContent() string {
+	return ""
+}
@@ -0,0 +1,223 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestECSFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Version: "7.94",
+		Args:    "nmap -sV 192.168.1.10",
+		Start:   1700000000,
+		Host: []Host{
+			{
+				StartTime:   1700000001,
+				EndTime:     1700000011,
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up"},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4", OSClass: []OSClass{{OSFamily: "Linux"}}}}},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "nginx", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"}},
+					},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "00:1A:2B:3C:4D:5F", AddressType: "mac"}},
+				Status:      HostStatus{State: "up"},
+				Port:        []Port{{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}}},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "Bulk lines"},
+		{name: "Error", err: errors.New("some error happened"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &ECSFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("ECSFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err != nil {
+				return
+			}
+			lines := strings.Split(strings.TrimSuffix(string(writer.data), "\n"), "\n")
+			if len(lines) != 6 {
+				t.Fatalf("ECSFormatter.Format() expected 6 lines, got %d", len(lines))
+			}
+			wantAction := `{"index":{"_index":"nmap-formatter","_id":"1700000000-192.168.1.10-tcp-80"}}`
+			if lines[0] != wantAction {
+				t.Errorf("ECSFormatter.Format() action = %s, want %s", lines[0], wantAction)
+			}
+			var doc ECSDocument
+			if err := json.Unmarshal([]byte(lines[1]), &doc); err != nil {
+				t.Fatalf("could not unmarshal ECS document: %v", err)
+			}
+			want := ECSDocument{
+				Timestamp: "2023-11-14T22:13:21Z",
+				ECS:       ECSVersionInfo{Version: ECSVersion},
+				Event: ECSEvent{
+					Kind:     "event",
+					Category: []string{"network"},
+					Type:     []string{"info"},
+					Module:   "nmap",
+					Dataset:  "nmap.port",
+					Start:    "2023-11-14T22:13:21Z",
+					End:      "2023-11-14T22:13:31Z",
+					Duration: 10000000000,
+				},
+				Observer: ECSObserver{Type: "scanner", Product: "nmap", Version: "7.94"},
+				Host: ECSHost{
+					IP:       []string{"192.168.1.10"},
+					MAC:      []string{"00:1A:2B:3C:4D:5E"},
+					Hostname: "web.local",
+					OS:       &ECSOS{Name: "Linux 5.0 - 5.4", Family: "Linux"},
+				},
+				Destination: ECSDestination{IP: "192.168.1.10", Port: 80},
+				Network:     ECSNetwork{Transport: "tcp", Type: "ipv4"},
+				Service:     ECSService{Name: "http", Version: "1.18.0", State: "open"},
+				NMAP: ECSNMAP{
+					Args:        "nmap -sV 192.168.1.10",
+					HostState:   "up",
+					StateReason: "syn-ack",
+					Product:     "nginx",
+					CPE:         []string{"cpe:/a:igor_sysoev:nginx:1.18.0"},
+				},
+			}
+			if !reflect.DeepEqual(doc, want) {
+				t.Errorf("ECSFormatter.Format() document = %+v, want %+v", doc, want)
+			}
+			if !strings.HasPrefix(lines[2], `{"index":{"_index":"nmap-formatter","_id":"1700000000-192.168.1.10-udp-53"}}`) {
+				t.Errorf("ECSFormatter.Format() wrong second action: %s", lines[2])
+			}
+			// Host without IP address is identified by its position
+			if !strings.HasPrefix(lines[4], `{"index":{"_index":"nmap-formatter","_id":"1700000000-host1-tcp-80"}}`) {
+				t.Errorf("ECSFormatter.Format() wrong third action: %s", lines[4])
+			}
+		})
+	}
+}
+
+func TestECSFormatter_Format_HTTPSink(t *testing.T) {
+	run := NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				Port:        []Port{{Protocol: "tcp", PortID: 80}, {Protocol: "udp", PortID: 53}},
+			},
+		},
+	}
+	delay := ecsRetryDelay
+	ecsRetryDelay = 0
+	defer func() { ecsRetryDelay = delay }()
+
+	tests := []struct {
+		name string
+		// responses are returned one by one, the last one is repeated
+		responses    []int
+		body         string
+		batchSize    int
+		retries      int
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "One batch", responses: []int{200}, body: `{"errors":false}`, wantRequests: 1},
+		{name: "Batch per document", responses: []int{200}, body: `{"errors":false}`, batchSize: 1, wantRequests: 2},
+		{name: "Retried after server error", responses: []int{503, 429, 200}, retries: 3, wantRequests: 3},
+		{name: "Retries exceeded", responses: []int{502}, retries: 2, wantErr: true, wantRequests: 3},
+		{name: "Client error is not retried", responses: []int{400}, retries: 3, wantErr: true, wantRequests: 1},
+		{name: "Failed items", responses: []int{200}, body: `{"errors":true}`, retries: 3, wantErr: true, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/x-ndjson" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+				}
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				status := tt.responses[len(tt.responses)-1]
+				if len(bodies) <= len(tt.responses) {
+					status = tt.responses[len(bodies)-1]
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			writer := &jsonLinesMockedWriter{}
+			f := &ECSFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					ECSOptions: ECSOutputOptions{URL: server.URL + "/_bulk", BatchSize: tt.batchSize, Retries: tt.retries},
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("ECSFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(bodies) != tt.wantRequests {
+				t.Errorf("ECSFormatter.Format() requests = %d, want %d", len(bodies), tt.wantRequests)
+			}
+			if len(writer.data) != 0 {
+				t.Errorf("ECSFormatter.Format() should not write to the output when URL is provided")
+			}
+			// Every document is preceded by action line
+			wantLines := 4
+			if tt.batchSize == 1 {
+				wantLines = 2
+			}
+			for _, body := range bodies {
+				if lines := strings.Count(body, "\n"); lines != wantLines {
+					t.Errorf("ECSFormatter.Format() lines in batch = %d, want %d", lines, wantLines)
+				}
+			}
+		})
+	}
+}
+
+func TestECSOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ECSOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: ECSOutputOptions{}, wantErr: false},
+		{name: "Valid URL", options: ECSOutputOptions{URL: "https://localhost:9200/_bulk", BatchSize: 100, Retries: 3}, wantErr: false},
+		{name: "Wrong URL scheme", options: ECSOutputOptions{URL: "ftp://localhost/_bulk"}, wantErr: true},
+		{name: "Negative batch size", options: ECSOutputOptions{BatchSize: -1}, wantErr: true},
+		{name: "Negative retries", options: ECSOutputOptions{Retries: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ECSOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// GEXFFormatter is struct defined for GEXF (Graph Exchange XML Format) Output use-case,
+// it is a native format of Gephi
+type GEXFFormatter struct {
+	config *Config
+}
+
+// gexf is a root element of GEXF 1.3 document
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description,omitempty"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string         `xml:"defaultedgetype,attr"`
+	Mode            string         `xml:"mode,attr"`
+	Attributes      gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode     `xml:"nodes>node"`
+	Edges           []gexfEdge     `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class     string          `xml:"class,attr"`
+	Attribute []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID     string  `xml:"id,attr"`
+	Source string  `xml:"source,attr"`
+	Target string  `xml:"target,attr"`
+	Kind   string  `xml:"kind,attr"`
+	Weight *string `xml:"weight,attr"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Format the data to GEXF and output it to appropriate io.Writer
+func (f *GEXFFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	_, err = f.config.Writer.Write([]byte(xml.Header))
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(f.config.Writer)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(newGEXF(&td.NMAPRun, newNetworkGraph(&td.NMAPRun)))
+	if err != nil {
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte("\n"))
+	return err
+}
+
+// newGEXF converts network graph to GEXF document, node label is a separate GEXF attribute,
+// the rest of node attributes are declared in `attributes` section
+func newGEXF(n *NMAPRun, g *networkGraph) *gexf {
+	doc := &gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta:    gexfMeta{Creator: "nmap-formatter", Description: n.Args},
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes:      gexfAttributes{Class: "node"},
+		},
+	}
+	for _, a := range networkGraphNodeAttributes {
+		if a.name == "label" {
+			continue
+		}
+		kind := a.kind
+		if kind == "int" {
+			kind = "integer"
+		}
+		doc.Graph.Attributes.Attribute = append(doc.Graph.Attributes.Attribute, gexfAttribute{ID: a.name, Title: a.name, Type: kind})
+	}
+
+	for _, node := range g.nodes {
+		gn := gexfNode{ID: node.id, Label: node.attributes["label"]}
+		for _, a := range networkGraphNodeAttributes {
+			if v, ok := node.attributes[a.name]; ok && a.name != "label" {
+				gn.AttValues = append(gn.AttValues, gexfAttValue{For: a.name, Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gn)
+	}
+	for _, e := range g.edges {
+		edge := gexfEdge{ID: e.id, Source: e.source, Target: e.target, Kind: e.kind}
+		if e.hasRoute {
+			weight := strconv.FormatFloat(e.weight, 'f', -1, 64)
+			edge.Weight = &weight
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+	return doc
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *GEXFFormatter) defaultTemplateContent() string {
+	return ""
+}
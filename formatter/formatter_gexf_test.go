@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGEXFFormatter_Format(t *testing.T) {
+	// Two hosts behind the same router, the first host is also a traceroute hop of the second host
+	run := NMAPRun{
+		Scanner: "nmap",
+		Args:    "nmap -sV --traceroute 192.168.1.0/24",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up"},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4"}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: PortService{Name: "http", Product: "nginx", Version: "1.18.0"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.5}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.25}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.20", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.75}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.5}, {TTL: 3, IPAddr: "192.168.1.20", RTT: 2}}},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Shared router",
+			wantOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <meta>
+    <creator>nmap-formatter</creator>
+    <description>nmap -sV --traceroute 192.168.1.0/24</description>
+  </meta>
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="node">
+      <attribute id="type" title="type" type="string"></attribute>
+      <attribute id="state" title="state" type="string"></attribute>
+      <attribute id="os" title="os" type="string"></attribute>
+      <attribute id="hostname" title="hostname" type="string"></attribute>
+      <attribute id="service" title="service" type="string"></attribute>
+      <attribute id="protocol" title="protocol" type="string"></attribute>
+      <attribute id="port" title="port" type="integer"></attribute>
+      <attribute id="product" title="product" type="string"></attribute>
+      <attribute id="version" title="version" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="scanner" label="nmap">
+        <attvalues>
+          <attvalue for="type" value="scanner"></attvalue>
+        </attvalues>
+      </node>
+      <node id="ip:192.168.1.10" label="192.168.1.10">
+        <attvalues>
+          <attvalue for="type" value="host"></attvalue>
+          <attvalue for="state" value="up"></attvalue>
+          <attvalue for="os" value="Linux 5.0 - 5.4"></attvalue>
+          <attvalue for="hostname" value="web.local"></attvalue>
+        </attvalues>
+      </node>
+      <node id="ip:192.168.1.10/tcp/80" label="80/tcp http">
+        <attvalues>
+          <attvalue for="type" value="service"></attvalue>
+          <attvalue for="state" value="open"></attvalue>
+          <attvalue for="service" value="http"></attvalue>
+          <attvalue for="protocol" value="tcp"></attvalue>
+          <attvalue for="port" value="80"></attvalue>
+          <attvalue for="product" value="nginx"></attvalue>
+          <attvalue for="version" value="1.18.0"></attvalue>
+        </attvalues>
+      </node>
+      <node id="ip:192.168.1.20" label="192.168.1.20">
+        <attvalues>
+          <attvalue for="type" value="host"></attvalue>
+          <attvalue for="state" value="up"></attvalue>
+        </attvalues>
+      </node>
+      <node id="ip:10.0.0.1" label="10.0.0.1">
+        <attvalues>
+          <attvalue for="type" value="hop"></attvalue>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="e0" source="ip:192.168.1.10" target="ip:192.168.1.10/tcp/80" kind="service"></edge>
+      <edge id="e1" source="scanner" target="ip:10.0.0.1" kind="route" weight="0.5"></edge>
+      <edge id="e2" source="ip:10.0.0.1" target="ip:192.168.1.10" kind="route" weight="1.25"></edge>
+      <edge id="e3" source="ip:192.168.1.10" target="ip:192.168.1.20" kind="route" weight="2"></edge>
+    </edges>
+  </graph>
+</gexf>
+`,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &GEXFFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("GEXFFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("GEXFFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
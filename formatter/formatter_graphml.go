@@ -0,0 +1,113 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// GraphMLFormatter is struct defined for GraphML Output use-case, the result can be
+// opened in network analysis tools like Gephi, yEd or NetworkX
+type GraphMLFormatter struct {
+	config *Config
+}
+
+// graphML is a root element of GraphML document
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey declares node or edge attribute
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Format the data to GraphML and output it to appropriate io.Writer
+func (f *GraphMLFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	_, err = f.config.Writer.Write([]byte(xml.Header))
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(f.config.Writer)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(newGraphML(newNetworkGraph(&td.NMAPRun)))
+	if err != nil {
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte("\n"))
+	return err
+}
+
+// newGraphML converts network graph to GraphML document, node attributes are declared as keys
+// with the same name, edges have `type` and `weight` (RTT of traceroute hop) attributes
+func newGraphML(g *networkGraph) *graphML {
+	doc := &graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{ID: "nmap", EdgeDefault: "directed"},
+	}
+	for _, a := range networkGraphNodeAttributes {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: a.name, For: "node", Name: a.name, Type: a.kind})
+	}
+	doc.Keys = append(
+		doc.Keys,
+		graphMLKey{ID: "edge_type", For: "edge", Name: "type", Type: "string"},
+		graphMLKey{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+	)
+
+	for _, n := range g.nodes {
+		node := graphMLNode{ID: n.id}
+		for _, a := range networkGraphNodeAttributes {
+			if v, ok := n.attributes[a.name]; ok {
+				node.Data = append(node.Data, graphMLData{Key: a.name, Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, e := range g.edges {
+		edge := graphMLEdge{
+			ID:     e.id,
+			Source: e.source,
+			Target: e.target,
+			Data:   []graphMLData{{Key: "edge_type", Value: e.kind}},
+		}
+		if e.hasRoute {
+			edge.Data = append(edge.Data, graphMLData{Key: "weight", Value: strconv.FormatFloat(e.weight, 'f', -1, 64)})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, edge)
+	}
+	return doc
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *GraphMLFormatter) defaultTemplateContent() string {
+	return ""
+}
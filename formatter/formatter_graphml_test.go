@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraphMLFormatter_Format(t *testing.T) {
+	// Two hosts behind the same router, the first host is also a traceroute hop of the second host
+	run := NMAPRun{
+		Scanner: "nmap",
+		Args:    "nmap -sV --traceroute 192.168.1.0/24",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up"},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4"}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: PortService{Name: "http", Product: "nginx", Version: "1.18.0"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.5}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.25}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.20", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.75}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.5}, {TTL: 3, IPAddr: "192.168.1.20", RTT: 2}}},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Shared router",
+			wantOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="type" for="node" attr.name="type" attr.type="string"></key>
+  <key id="label" for="node" attr.name="label" attr.type="string"></key>
+  <key id="state" for="node" attr.name="state" attr.type="string"></key>
+  <key id="os" for="node" attr.name="os" attr.type="string"></key>
+  <key id="hostname" for="node" attr.name="hostname" attr.type="string"></key>
+  <key id="service" for="node" attr.name="service" attr.type="string"></key>
+  <key id="protocol" for="node" attr.name="protocol" attr.type="string"></key>
+  <key id="port" for="node" attr.name="port" attr.type="int"></key>
+  <key id="product" for="node" attr.name="product" attr.type="string"></key>
+  <key id="version" for="node" attr.name="version" attr.type="string"></key>
+  <key id="edge_type" for="edge" attr.name="type" attr.type="string"></key>
+  <key id="weight" for="edge" attr.name="weight" attr.type="double"></key>
+  <graph id="nmap" edgedefault="directed">
+    <node id="scanner">
+      <data key="type">scanner</data>
+      <data key="label">nmap</data>
+    </node>
+    <node id="ip:192.168.1.10">
+      <data key="type">host</data>
+      <data key="label">192.168.1.10</data>
+      <data key="state">up</data>
+      <data key="os">Linux 5.0 - 5.4</data>
+      <data key="hostname">web.local</data>
+    </node>
+    <node id="ip:192.168.1.10/tcp/80">
+      <data key="type">service</data>
+      <data key="label">80/tcp http</data>
+      <data key="state">open</data>
+      <data key="service">http</data>
+      <data key="protocol">tcp</data>
+      <data key="port">80</data>
+      <data key="product">nginx</data>
+      <data key="version">1.18.0</data>
+    </node>
+    <node id="ip:192.168.1.20">
+      <data key="type">host</data>
+      <data key="label">192.168.1.20</data>
+      <data key="state">up</data>
+    </node>
+    <node id="ip:10.0.0.1">
+      <data key="type">hop</data>
+      <data key="label">10.0.0.1</data>
+    </node>
+    <edge id="e0" source="ip:192.168.1.10" target="ip:192.168.1.10/tcp/80">
+      <data key="edge_type">service</data>
+    </edge>
+    <edge id="e1" source="scanner" target="ip:10.0.0.1">
+      <data key="edge_type">route</data>
+      <data key="weight">0.5</data>
+    </edge>
+    <edge id="e2" source="ip:10.0.0.1" target="ip:192.168.1.10">
+      <data key="edge_type">route</data>
+      <data key="weight">1.25</data>
+    </edge>
+    <edge id="e3" source="ip:192.168.1.10" target="ip:192.168.1.20">
+      <data key="edge_type">route</data>
+      <data key="weight">2</data>
+    </edge>
+  </graph>
+</graphml>
+`,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &GraphMLFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("GraphMLFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("GraphMLFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
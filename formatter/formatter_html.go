@@ -4,6 +4,8 @@ import (
 	// Used in this place to have all required functionality within one binary file. No need for separate folders/files, just embed template
 	_ "embed"
 	"html/template"
+
+	"oss.terrastruct.com/d2/d2themes/d2themescatalog"
 )
 
 // HTMLFormatter is struct defined for HTML Output use-case
@@ -18,7 +20,11 @@ var HTMLSimpleTemplate string
 
 // Format the data and output it to appropriate io.Writer
 func (f *HTMLFormatter) Format(td *TemplateData, templateContent string) error {
-	tmpl, err := template.New("html").Parse(templateContent)
+	tmpl, err := template.New("html").Funcs(
+		template.FuncMap{
+			"topology_svg": htmlTopologySVG,
+		},
+	).Parse(templateContent)
 	if err != nil {
 		return err
 	}
@@ -28,3 +34,22 @@ func (f *HTMLFormatter) Format(td *TemplateData, templateContent string) error {
 func (f *HTMLFormatter) defaultTemplateContent() string {
 	return HTMLSimpleTemplate
 }
+
+// htmlTopologySVG renders topology of the scan to inline SVG, where hosts link to their sections,
+// D2 options are respected, dark theme is used by default in dark mode
+func htmlTopologySVG(n NMAPRun, o OutputOptions) (template.HTML, error) {
+	options := o.D2LangOptions
+	if options.ThemeID == 0 && o.HTMLOptions.DarkMode {
+		options.ThemeID = d2themescatalog.DarkMauve.ID
+	}
+	graph, err := d2Graph(&n, &options, &o.DotOptions, true)
+	if err != nil {
+		return "", err
+	}
+	svg, err := d2SVG(graph, &options)
+	if err != nil {
+		return "", err
+	}
+	// SVG is generated by D2 renderer, all labels are escaped
+	return template.HTML(svg), nil
+}
@@ -548,6 +548,97 @@ func TestHTMLFormatter_Format(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Collapsible hosts, port groups and hidden controls",
+			f:    &HTMLFormatter{},
+			args: args{
+				td: &TemplateData{
+					NMAPRun: NMAPRun{
+						Host: []Host{
+							{
+								HostAddress: []HostAddress{{Address: "192.168.1.1"}},
+								HostNames:   HostNames{HostName: []HostName{{Name: "rdp.local"}}},
+								Status:      HostStatus{State: "up"},
+								Port: []Port{
+									{PortID: 3389, Protocol: "tcp", State: PortState{State: "open"}, Service: PortService{Name: "ms-wbt-server"}},
+									{PortID: 22, Protocol: "tcp", State: PortState{State: "filtered"}, Service: PortService{Name: "ssh"}},
+								},
+							},
+							{
+								HostAddress: []HostAddress{{Address: "192.168.1.2"}},
+								Status:      HostStatus{State: "down"},
+							},
+						},
+					},
+					OutputOptions: OutputOptions{},
+				},
+			},
+			wantErr: false,
+			validate: func(f *HTMLFormatter, output string, t *testing.T) {
+				wantContains := []string{
+					// Controls are displayed only if script is executed
+					`<div id="report-controls" hidden>`,
+					`<details class="host" data-toc="0" data-search="192.168.1.1 rdp.local up 3389/tcp ms-wbt-server   22/tcp ssh  " open>`,
+					`<details class="host" data-toc="1" data-search="192.168.1.2  down" open>`,
+					`<tbody class="port-group" data-state="open" data-service="ms-wbt-server">`,
+					`<tbody class="port-group" data-state="filtered" data-service="ssh">`,
+					"<script>",
+				}
+				for _, want := range wantContains {
+					if !strings.Contains(output, want) {
+						t.Errorf("Expected output to contain %q", want)
+					}
+				}
+			},
+		},
+		{
+			name: "Topology diagram",
+			f:    &HTMLFormatter{},
+			args: args{
+				td: &TemplateData{
+					NMAPRun: NMAPRun{
+						Host: []Host{
+							{
+								HostAddress: []HostAddress{{Address: "192.168.1.1"}},
+								Status:      HostStatus{State: "up"},
+							},
+						},
+					},
+					OutputOptions: OutputOptions{},
+				},
+			},
+			wantErr: false,
+			validate: func(f *HTMLFormatter, output string, t *testing.T) {
+				for _, want := range []string{`<li><a href="#topology">Topology</a></li>`, `<div id="topology-diagram">`, `<a href="#0" xlink:href="#0">`} {
+					if !strings.Contains(output, want) {
+						t.Errorf("Expected output to contain %q", want)
+					}
+				}
+			},
+		},
+		{
+			name: "Skip topology diagram",
+			f:    &HTMLFormatter{},
+			args: args{
+				td: &TemplateData{
+					NMAPRun: NMAPRun{
+						Host: []Host{
+							{
+								HostAddress: []HostAddress{{Address: "192.168.1.1"}},
+								Status:      HostStatus{State: "up"},
+							},
+						},
+					},
+					OutputOptions: OutputOptions{HTMLOptions: HTMLOutputOptions{SkipTopology: true}},
+				},
+			},
+			wantErr: false,
+			validate: func(f *HTMLFormatter, output string, t *testing.T) {
+				if strings.Contains(output, `<div id="topology-diagram">`) || strings.Contains(output, "<svg") {
+					t.Errorf("Expected output not to contain topology diagram")
+				}
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -12,10 +12,15 @@ type JSONFormatter struct {
 
 // Format the data and output it to appropriate io.Writer
 func (f *JSONFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	var v interface{} = td.NMAPRun
+	if td.OutputOptions.JSONOptions.Envelope {
+		v = f.envelope(td)
+	}
+
 	// Use snake_case encoder if requested
 	if td.OutputOptions.JSONOptions.SnakeCase {
 		encoder := newSnakeCaseEncoder(f.config.Writer, td.OutputOptions.JSONOptions.PrettyPrint)
-		return encoder.Encode(td.NMAPRun)
+		return encoder.Encode(v)
 	}
 
 	// Default JSON encoding
@@ -25,7 +30,7 @@ func (f *JSONFormatter) Format(td *TemplateData, templateContent string) (err er
 		// space size = 2
 		encoder.SetIndent("", "  ")
 	}
-	err = encoder.Encode(td.NMAPRun)
+	err = encoder.Encode(v)
 	if err != nil {
 		return err
 	}
@@ -33,6 +38,20 @@ func (f *JSONFormatter) Format(td *TemplateData, templateContent string) (err er
 	return
 }
 
+// envelope wraps NMAPRun with schema version, nmap-formatter version and options used
+func (f *JSONFormatter) envelope(td *TemplateData) JSONEnvelope {
+	return JSONEnvelope{
+		SchemaVersion: JSONSchemaVersion,
+		ToolVersion:   f.config.CurrentVersion,
+		Options: JSONEnvelopeOptions{
+			SnakeCase:         td.OutputOptions.JSONOptions.SnakeCase,
+			SkipDownHosts:     f.config.SkipDownHosts,
+			FilterExpressions: f.config.FilterExpressions,
+		},
+		NMAPRun: td.NMAPRun,
+	}
+}
+
 func (f *JSONFormatter) defaultTemplateContent() string {
 	return ""
 }
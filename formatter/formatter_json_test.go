@@ -89,6 +89,31 @@ func TestJSONFormatter_Format(t *testing.T) {
 }
 `,
 		},
+		{
+			name: "Envelope (snake_case)",
+			f: &JSONFormatter{
+				&Config{
+					Writer:            &writer,
+					CurrentVersion:    "3.1.0",
+					SkipDownHosts:     true,
+					FilterExpressions: []string{"any(.Port, { .PortID == 80 })"},
+				},
+			},
+			args: args{
+				td: &TemplateData{
+					NMAPRun: NMAPRun{},
+					OutputOptions: OutputOptions{
+						JSONOptions: JSONOutputOptions{
+							SnakeCase: true,
+							Envelope:  true,
+						},
+					},
+				},
+			},
+			wantErr:    false,
+			err:        nil,
+			wantOutput: "{\"schema_version\":\"" + JSONSchemaVersion + "\",\"tool_version\":\"3.1.0\",\"options\":{\"snake_case\":true,\"skip_down_hosts\":true,\"filter_expressions\":[\"any(.Port, { .PortID == 80 })\"]},\"nmaprun\":{\"scanner\":\"\",\"args\":\"\",\"start\":0,\"start_str\":\"\",\"version\":\"\",\"scan_info\":{\"type\":\"\",\"protocol\":\"\",\"num_services\":0,\"services\":\"\"},\"host\":null,\"verbose\":{\"level\":0},\"debugging\":{\"level\":0},\"run_stats\":{\"finished\":{\"time\":0,\"time_str\":\"\",\"elapsed\":0,\"summary\":\"\",\"exit\":\"\"},\"hosts\":{\"up\":0,\"down\":0,\"total\":0}}}}\n",
+		},
 		{
 			name: "Error",
 			f: &JSONFormatter{
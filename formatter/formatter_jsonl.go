@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	// JSONLinesHostRecord defines that every line of JSON Lines output is a host record
+	JSONLinesHostRecord = "host"
+	// JSONLinesPortRecord defines that every line of JSON Lines output is a port record
+	JSONLinesPortRecord = "port"
+)
+
+// JSONLinesFormatter is struct defined for JSON Lines Output use-case,
+// every line of the output is a self-contained JSON document
+type JSONLinesFormatter struct {
+	config *Config
+}
+
+// JSONLinesScan contains scan meta-information that is attached to every record
+type JSONLinesScan struct {
+	ScanID   string
+	Scanner  string
+	Version  string
+	Args     string
+	Start    int
+	StartStr string
+}
+
+// JSONLinesHost is a single line record that contains the whole host
+type JSONLinesHost struct {
+	Scan JSONLinesScan
+	Host Host
+}
+
+// JSONLinesPort is a single line record that contains a port and main information about its host
+type JSONLinesPort struct {
+	Scan        JSONLinesScan
+	HostAddress []HostAddress
+	HostNames   HostNames
+	Status      HostStatus
+	Port        Port
+}
+
+// Format the data to JSON Lines and output it to appropriate io.Writer
+func (f *JSONLinesFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	encode := jsonLinesEncoder(f.config.Writer, td.OutputOptions.JSONOptions.SnakeCase)
+	for _, record := range f.records(td) {
+		err = encode(record)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// records converts NMAPRun into a list of host or port records depending on chosen output options
+func (f *JSONLinesFormatter) records(td *TemplateData) (records []interface{}) {
+	scan := JSONLinesScan{
+		ScanID:   scanIdentifier(&td.OutputOptions),
+		Scanner:  td.NMAPRun.Scanner,
+		Version:  td.NMAPRun.Version,
+		Args:     td.NMAPRun.Args,
+		Start:    td.NMAPRun.Start,
+		StartStr: td.NMAPRun.StartStr,
+	}
+	for i := range td.NMAPRun.Host {
+		host := &td.NMAPRun.Host[i]
+		if td.OutputOptions.JSONLinesOptions.Record != JSONLinesPortRecord {
+			records = append(records, JSONLinesHost{Scan: scan, Host: *host})
+			continue
+		}
+		for j := range host.Port {
+			records = append(records, JSONLinesPort{
+				Scan:        scan,
+				HostAddress: host.HostAddress,
+				HostNames:   host.HostNames,
+				Status:      host.Status,
+				Port:        host.Port[j],
+			})
+		}
+	}
+	return records
+}
+
+// jsonLinesEncoder returns a function that encodes one record per line
+func jsonLinesEncoder(w io.Writer, snakeCase bool) func(v interface{}) error {
+	if snakeCase {
+		return newSnakeCaseEncoder(w, false).Encode
+	}
+	return json.NewEncoder(w).Encode
+}
+
+// Validate checks whether JSON Lines record type is supported
+func (o *JSONLinesOutputOptions) Validate() error {
+	switch o.Record {
+	case "", JSONLinesHostRecord, JSONLinesPortRecord:
+		return nil
+	}
+	return fmt.Errorf("unknown jsonl record type: %s, please choose host/port", o.Record)
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *JSONLinesFormatter) defaultTemplateContent() string {
+	return ""
+}
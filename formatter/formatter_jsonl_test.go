@@ -0,0 +1,165 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJSONLinesFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Args:    "nmap -sV",
+		Start:   1,
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{
+					{Address: "127.0.0.1", AddressType: "ipv4"},
+				},
+				Status: HostStatus{State: "up"},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 22, State: PortState{State: "open"}},
+					{Protocol: "tcp", PortID: 80, State: PortState{State: "closed"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{
+					{Address: "127.0.0.2", AddressType: "ipv4"},
+				},
+				Status: HostStatus{State: "down"},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    OutputOptions
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Port records",
+			options: OutputOptions{
+				JSONLinesOptions:    JSONLinesOutputOptions{Record: JSONLinesPortRecord},
+				SqliteOutputOptions: SqliteOutputOptions{ScanIdentifier: "scan1"},
+			},
+			wantOutput: `{"Scan":{"ScanID":"scan1","Scanner":"nmap","Version":"","Args":"nmap -sV","Start":1,"StartStr":""},"HostAddress":[{"Address":"127.0.0.1","AddressType":"ipv4","Vendor":""}],"HostNames":{"HostName":null},"Status":{"State":"up","Reason":""},"Port":{"Protocol":"tcp","PortID":22,"State":{"State":"open","Reason":"","ReasonTTL":""},"Service":{"Name":"","Product":"","Version":"","ExtraInfo":"","Method":"","Conf":"","CPE":null},"Script":null}}
+{"Scan":{"ScanID":"scan1","Scanner":"nmap","Version":"","Args":"nmap -sV","Start":1,"StartStr":""},"HostAddress":[{"Address":"127.0.0.1","AddressType":"ipv4","Vendor":""}],"HostNames":{"HostName":null},"Status":{"State":"up","Reason":""},"Port":{"Protocol":"tcp","PortID":80,"State":{"State":"closed","Reason":"","ReasonTTL":""},"Service":{"Name":"","Product":"","Version":"","ExtraInfo":"","Method":"","Conf":"","CPE":null},"Script":null}}
+`,
+		},
+		{
+			name: "Port records (snake_case)",
+			options: OutputOptions{
+				JSONOptions:         JSONOutputOptions{SnakeCase: true},
+				JSONLinesOptions:    JSONLinesOutputOptions{Record: JSONLinesPortRecord},
+				SqliteOutputOptions: SqliteOutputOptions{ScanIdentifier: "scan1"},
+			},
+			wantOutput: `{"scan":{"scan_id":"scan1","scanner":"nmap","version":"","args":"nmap -sV","start":1,"start_str":""},"host_address":[{"address":"127.0.0.1","address_type":"ipv4","vendor":""}],"host_names":{"host_name":null},"status":{"state":"up","reason":""},"port":{"protocol":"tcp","port_id":22,"state":{"state":"open","reason":"","reason_ttl":""},"service":{"name":"","product":"","version":"","extra_info":"","method":"","conf":"","cpe":null},"script":null}}
+{"scan":{"scan_id":"scan1","scanner":"nmap","version":"","args":"nmap -sV","start":1,"start_str":""},"host_address":[{"address":"127.0.0.1","address_type":"ipv4","vendor":""}],"host_names":{"host_name":null},"status":{"state":"up","reason":""},"port":{"protocol":"tcp","port_id":80,"state":{"state":"closed","reason":"","reason_ttl":""},"service":{"name":"","product":"","version":"","extra_info":"","method":"","conf":"","cpe":null},"script":null}}
+`,
+		},
+		{
+			name: "Error",
+			options: OutputOptions{
+				SqliteOutputOptions: SqliteOutputOptions{ScanIdentifier: "scan1"},
+			},
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &JSONLinesFormatter{
+				config: &Config{
+					Writer: writer,
+				},
+			}
+			td := &TemplateData{
+				NMAPRun:       run,
+				OutputOptions: tt.options,
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("JSONLinesFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("JSONLinesFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestJSONLinesFormatter_records(t *testing.T) {
+	run := NMAPRun{
+		Host: []Host{
+			{Port: []Port{{PortID: 22}, {PortID: 80}}},
+			{Port: []Port{}},
+		},
+	}
+	tests := []struct {
+		name      string
+		record    string
+		wantCount int
+	}{
+		{
+			name:      "Default is host record",
+			record:    "",
+			wantCount: 2,
+		},
+		{
+			name:      "Host records",
+			record:    JSONLinesHostRecord,
+			wantCount: 2,
+		},
+		{
+			name:      "Port records skip hosts without ports",
+			record:    JSONLinesPortRecord,
+			wantCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &JSONLinesFormatter{}
+			records := f.records(&TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					JSONLinesOptions: JSONLinesOutputOptions{Record: tt.record},
+				},
+			})
+			if len(records) != tt.wantCount {
+				t.Errorf("JSONLinesFormatter.records() count = %d, want %d", len(records), tt.wantCount)
+			}
+			for _, r := range records {
+				_, isPort := r.(JSONLinesPort)
+				if isPort != (tt.record == JSONLinesPortRecord) {
+					t.Errorf("JSONLinesFormatter.records() unexpected record type %T", r)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONLinesOutputOptions_Validate(t *testing.T) {
+	for record, wantErr := range map[string]bool{"": false, "host": false, "port": false, "script": true} {
+		o := &JSONLinesOutputOptions{Record: record}
+		if err := o.Validate(); (err != nil) != wantErr {
+			t.Errorf("JSONLinesOutputOptions.Validate(%q) error = %v, wantErr %v", record, err, wantErr)
+		}
+	}
+}
+
+type jsonLinesMockedWriter struct {
+	data []byte
+	err  error
+}
+
+func (w *jsonLinesMockedWriter) Write(p []byte) (n int, err error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+func (w *jsonLinesMockedWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package formatter
+
+import (
+	// Used in this place to have all required functionality within one binary file. No need for separate folders/files, just embed template
+	_ "embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// LaTeXFormatter is a formatter struct used to deliver LaTeX document
+type LaTeXFormatter struct {
+	config *Config
+}
+
+// LaTeXTemplate variable is used to store latex.tmpl embed file contents
+//
+//go:embed resources/templates/latex.tmpl
+var LaTeXTemplate string
+
+// latexReplacer escapes characters that have special meaning in LaTeX,
+// replacement is done in a single pass, so inserted braces are not escaped again
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`$`, `\$`,
+	`&`, `\&`,
+	`#`, `\#`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`^`, `\textasciicircum{}`,
+	`~`, `\textasciitilde{}`,
+	`<`, `\textless{}`,
+	`>`, `\textgreater{}`,
+	`|`, `\textbar{}`,
+)
+
+// latexBlankLines matches more than one blank line, which are produced by template conditions
+var latexBlankLines = regexp.MustCompile(`\n{3,}`)
+
+// Format the data and output it to appropriate io.Writer
+func (f *LaTeXFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	tmpl := template.New("latex")
+	f.defineTemplateFunctions(tmpl)
+	tmpl, err = tmpl.Parse(templateContent)
+	if err != nil {
+		return
+	}
+	output := &strings.Builder{}
+	err = tmpl.Execute(output, td)
+	if err != nil {
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte(latexBlankLines.ReplaceAllString(output.String(), "\n\n")))
+	return err
+}
+
+func (f *LaTeXFormatter) defaultTemplateContent() string {
+	return LaTeXTemplate
+}
+
+func (f *LaTeXFormatter) defineTemplateFunctions(tmpl *template.Template) {
+	tmpl.Funcs(
+		template.FuncMap{
+			"tex":       latexEscape,
+			"tex_code":  latexCode,
+			"tex_title": latexHostTitle,
+		},
+	)
+}
+
+// latexEscape escapes LaTeX special characters, so the value is printed as is
+func latexEscape(v string) string {
+	return latexReplacer.Replace(v)
+}
+
+// latexCode escapes the value and keeps its layout: spaces are preserved, lines are
+// separated with line breaks and long comma separated values can be broken after a comma,
+// it's meant to be used with monospace font (script output, arguments)
+func latexCode(v string) string {
+	lines := strings.Split(strings.Trim(strings.ReplaceAll(v, "\r\n", "\n"), "\n"), "\n")
+	for i, line := range lines {
+		line = latexEscape(line)
+		line = strings.ReplaceAll(line, " ", `\ `)
+		line = strings.ReplaceAll(line, "\t", `\ \ \ \ `)
+		line = strings.ReplaceAll(line, ",", `,\allowbreak{}`)
+		if line == "" {
+			// Empty line can't be ended with a line break
+			line = `\mbox{}`
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\\\\\n")
+}
+
+// latexHostTitle returns escaped host section title
+func latexHostTitle(h *Host) string {
+	title := h.JoinedAddresses("/")
+	for i := range h.HostNames.HostName {
+		title += fmt.Sprintf(" / %s", h.HostNames.HostName[i].Name)
+	}
+	title += fmt.Sprintf(" (%s)", h.Status.State)
+	return latexEscape(title)
+}
@@ -0,0 +1,200 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLaTeXFormatter_Format(t *testing.T) {
+	// Values with characters that have to be escaped
+	run := NMAPRun{
+		Scanner:  "nmap",
+		Args:     "nmap -sV -O --traceroute 10.0.0.0/23",
+		Start:    1700000000,
+		StartStr: "Tue Nov 14 22:13:20 2023",
+		Version:  "7.94",
+		RunStats: RunStats{
+			Finished: Finished{Time: 1700000012, Elapsed: 12.35},
+			Hosts:    StatHosts{Up: 2, Down: 1, Total: 3},
+		},
+		Host: []Host{
+			{
+				StartTime:   1700000001,
+				EndTime:     1700000011,
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web_1.local", Type: "PTR"}}},
+				Status:      HostStatus{State: "up", Reason: "syn-ack"},
+				Distance:    Distance{Value: 2},
+				OS: OS{
+					OSMatch: []OSMatch{{Name: "Linux 5.X", Accuracy: "95", Line: "100"}},
+					OSClass: []OSClass{{OSFamily: "Linux"}},
+				},
+				Trace: Trace{
+					Port:     80,
+					Protocol: "tcp",
+					Hops:     []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.5}, {TTL: 2, IPAddr: "10.0.0.1", RTT: 1.25}},
+				},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "R&D server", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"}},
+						Script:   []Script{{ID: "http_title", Output: "\n  Title: 100% {fun}\n\n  Path: C:\\web"}},
+					},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed", Reason: "port-unreach"}, Service: PortService{Name: "domain"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up", Reason: "echo-reply"},
+				OS:          OS{OSClass: []OSClass{{OSFamily: "Windows"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.75}, {TTL: 2, IPAddr: "10.0.1.2", RTT: 1.5}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 445, State: PortState{State: "open", Reason: "syn-ack"}, Service: PortService{Name: "microsoft-ds"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.3", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "down", Reason: "no-response"},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      LaTeXOutputOptions
+		template     string
+		err          error
+		wantErr      bool
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "Default template",
+			wantContains: []string{
+				"\\title{NMAP Scan Result: Tue Nov 14 22:13:20 2023}\n",
+				"\\maketitle\n",
+				"\\tableofcontents\n",
+				"\\section{Scan Summary}\n",
+				"Arguments & \\texttt{nmap\\ -sV\\ -O\\ --traceroute\\ 10.0.0.0/23} \\\\\n",
+				"\\section{10.0.0.1 / 00:1A:2B:3C:4D:5E / web\\_1.local (up)}\n",
+				"Hostnames & web\\_1.local (PTR) \\\\\nOS & Linux 5.X (95\\%) \\\\\n\\bottomrule\n",
+				"\\endhead\n80 & tcp & open & http & syn-ack & R\\&D server & 1.18.0 &  \\\\\n",
+				"\\subsection{Traceroute}\n",
+				"1 & 0.5 & 10.0.0.254 &  \\\\\n2 & 1.25 & 10.0.0.1 &  \\\\\n\\bottomrule\n",
+				"\\subsection{Misc Metrics}\n",
+				"\\subsubsection{Port 80/tcp}\n",
+				"\\paragraph{http\\_title}\n\n\\noindent{\\ttfamily\\small \\ \\ Title:\\ 100\\%\\ \\{fun\\}\\\\\n\\mbox{}\\\\\n\\ \\ Path:\\ C:\\textbackslash{}web\\par}\n",
+				"\\end{document}\n",
+			},
+			wantMissing: []string{"\n\n\n"},
+		},
+		{
+			name: "Skip options",
+			options: LaTeXOutputOptions{
+				SkipHeader:      true,
+				SkipTOC:         true,
+				SkipSummary:     true,
+				SkipTraceroute:  true,
+				SkipMetrics:     true,
+				SkipPortScripts: true,
+			},
+			wantContains: []string{"\\section{10.0.0.1 / 00:1A:2B:3C:4D:5E / web\\_1.local (up)}\n", "\\subsection{Ports}\n"},
+			wantMissing: []string{
+				"\\maketitle",
+				"\\tableofcontents",
+				"Scan Summary",
+				"Traceroute",
+				"Misc Metrics",
+				"\\subsection{Scripts}",
+			},
+		},
+		{
+			name:         "Custom template",
+			template:     `\section{ {{- tex_title (index .NMAPRun.Host 0) }}}`,
+			wantContains: []string{`\section{10.0.0.1 / 00:1A:2B:3C:4D:5E / web\_1.local (up)}`},
+		},
+		{
+			name:     "Wrong template",
+			template: `{{ .Unknown `,
+			wantErr:  true,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &LaTeXFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					LaTeXOptions: tt.options,
+				},
+			}
+			templateContent := tt.template
+			if templateContent == "" {
+				templateContent = f.defaultTemplateContent()
+			}
+			if err := f.Format(td, templateContent); (err != nil) != tt.wantErr {
+				t.Errorf("LaTeXFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("LaTeXFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("LaTeXFormatter.Format() output should not contain %q", missing)
+				}
+			}
+		})
+	}
+}
+
+func Test_latexEscape(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{name: "Plain text", v: "OpenSSH 8.9p1", want: "OpenSSH 8.9p1"},
+		{name: "Special characters", v: `#$%&_{}`, want: `\#\$\%\&\_\{\}`},
+		{name: "Backslash is not escaped twice", v: `C:\web`, want: `C:\textbackslash{}web`},
+		{name: "Text commands", v: "~^<>|", want: `\textasciitilde{}\textasciicircum{}\textless{}\textgreater{}\textbar{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latexEscape(tt.v); got != tt.want {
+				t.Errorf("latexEscape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_latexCode(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{name: "Empty", v: "", want: `\mbox{}`},
+		{name: "Spaces are kept", v: "a  b", want: `a\ \ b`},
+		{name: "Comma separated values can be broken", v: "1-1000,3389", want: `1-1000,\allowbreak{}3389`},
+		{name: "Multiple lines", v: "\nfirst\r\n\nsecond\n", want: "first\\\\\n\\mbox{}\\\\\nsecond"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latexCode(tt.v); got != tt.want {
+				t.Errorf("latexCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
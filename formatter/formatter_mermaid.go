@@ -0,0 +1,178 @@
+package formatter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// MermaidDefaultDirection is a default direction of Mermaid flowchart (left to right)
+const MermaidDefaultDirection = "LR"
+
+// MermaidFormatter is struct defined for Mermaid diagram Output use-case
+type MermaidFormatter struct {
+	config *Config
+}
+
+// Format the data to Mermaid flowchart and output it to appropriate io.Writer
+func (f *MermaidFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	_, err = f.config.Writer.Write([]byte(mermaidFlowchart(&td.NMAPRun, &td.OutputOptions.MermaidOptions, &td.OutputOptions.DotOptions)))
+	return
+}
+
+// mermaidFlowchart builds a flowchart of scanner -> hops -> hosts -> ports, port state colors are taken from Dot options
+func mermaidFlowchart(n *NMAPRun, options *MermaidOutputOptions, colors *DotOutputOptions) string {
+	direction := options.Direction
+	if direction == "" {
+		direction = MermaidDefaultDirection
+	}
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "flowchart %s\n", direction)
+
+	// Port state colors are the same as in the Dot (Graphviz) output
+	for _, state := range []string{"open", "filtered", "closed", "other"} {
+		color := colors.portStateColor(&Port{State: PortState{State: state}})
+		fmt.Fprintf(b, "    classDef %s stroke:%s,color:%s\n", state, color, color)
+	}
+
+	fmt.Fprintf(b, "    %s{{%s}}\n", mermaidID("scanner"), mermaidLabel(n.Scanner))
+
+	hops := n.AllHops()
+	hopKeys := make([]string, 0, len(hops))
+	for k := range hops {
+		hopKeys = append(hopKeys, k)
+	}
+	sort.Strings(hopKeys)
+	for _, k := range hopKeys {
+		fmt.Fprintf(b, "    %s((%s))\n", mermaidID("hop"+k), mermaidLabel(k))
+	}
+
+	if options.GroupBySubnet {
+		groups := hostsBySubnet(n.Host, subnetPrefix(options.SubnetPrefix))
+		subnets := make([]string, 0, len(groups))
+		for s := range groups {
+			subnets = append(subnets, s)
+		}
+		sort.Strings(subnets)
+		for _, s := range subnets {
+			indent := "    "
+			if s != "" {
+				fmt.Fprintf(b, "    subgraph %s [%s]\n", mermaidID("subnet"+s), mermaidLabel(s))
+				indent = "        "
+			}
+			for _, i := range groups[s] {
+				mermaidHostNode(b, indent, i, &n.Host[i])
+			}
+			if s != "" {
+				b.WriteString("    end\n")
+			}
+		}
+	} else {
+		for i := range n.Host {
+			mermaidHostNode(b, "    ", i, &n.Host[i])
+		}
+	}
+
+	// Hops are shared between the routes, so each edge is written only once
+	edges := map[string]bool{}
+	for i := range n.Host {
+		host := &n.Host[i]
+		for j := range host.Port {
+			port := &host.Port[j]
+			fmt.Fprintf(
+				b,
+				"    %s[%s]:::%s\n",
+				mermaidID(portNodeID(i, port)),
+				mermaidLabel(fmt.Sprintf("%s/%d (%s)", port.Protocol, port.PortID, port.State.State)),
+				mermaidPortClass(port),
+			)
+			fmt.Fprintf(b, "    %s --- %s\n", mermaidID(fmt.Sprintf("srv%d", i)), mermaidID(portNodeID(i, port)))
+		}
+
+		route := hopList(host.Trace.Hops, "scanner", "srv", i)
+		sources := make([]string, 0, len(route))
+		for k := range route {
+			sources = append(sources, k)
+		}
+		sort.Strings(sources)
+		for _, source := range sources {
+			edge := fmt.Sprintf("    %s --> %s\n", mermaidID(source), mermaidID(route[source]))
+			if edges[edge] {
+				continue
+			}
+			edges[edge] = true
+			b.WriteString(edge)
+		}
+	}
+	return b.String()
+}
+
+// mermaidHostNode writes host node definition (hexagon shape)
+func mermaidHostNode(b *strings.Builder, indent string, index int, h *Host) {
+	label := h.JoinedAddresses("/")
+	if hostnames := h.JoinedHostNames("/"); hostnames != "" {
+		label = fmt.Sprintf("%s\n(%s)", label, hostnames)
+	}
+	fmt.Fprintf(b, "%s%s{{%s}}\n", indent, mermaidID(fmt.Sprintf("srv%d", index)), mermaidLabel(label))
+}
+
+// mermaidPortClass returns a class name (defined by classDef) based on port state
+func mermaidPortClass(port *Port) string {
+	switch port.State.State {
+	case "open", "filtered", "closed":
+		return port.State.State
+	}
+	return "other"
+}
+
+// mermaidID converts any value to safe Mermaid node identifier. Only ASCII letters, digits and
+// underscores are kept, `n_` prefix avoids clashes with Mermaid keywords (like `end`)
+func mermaidID(v string) string {
+	var b strings.Builder
+	for _, r := range v {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			// Replacing instead of removing keeps `10.1.1.1` and `101.1.1` different
+			b.WriteRune('_')
+		}
+	}
+	if strings.Trim(b.String(), "_") == "" {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(v))
+		return fmt.Sprintf("n_x%x", h.Sum64())
+	}
+	return "n_" + b.String()
+}
+
+// mermaidLabel returns quoted node label, special characters are replaced with Mermaid entity codes
+func mermaidLabel(v string) string {
+	r := strings.NewReplacer(
+		"#", "#35;",
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\r", "",
+		"\n", "<br/>",
+	)
+	return `"` + r.Replace(v) + `"`
+}
+
+// Validate checks whether flowchart direction and subnet prefix are correct
+func (o *MermaidOutputOptions) Validate() error {
+	switch o.Direction {
+	case "", "TB", "TD", "BT", "RL", "LR":
+	default:
+		return fmt.Errorf("unknown mermaid direction: %s, please choose TB/TD/BT/RL/LR", o.Direction)
+	}
+	if err := validateSubnetPrefix(o.SubnetPrefix); err != nil {
+		return fmt.Errorf("wrong mermaid subnet prefix: %v", err)
+	}
+	return nil
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *MermaidFormatter) defaultTemplateContent() string {
+	return ""
+}
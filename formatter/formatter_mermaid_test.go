@@ -0,0 +1,194 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMermaidFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}},
+					{Protocol: "tcp", PortID: 443, State: PortState{State: "filtered"}},
+				},
+				Trace: Trace{
+					Hops: []Hop{
+						{TTL: 1, IPAddr: "10.0.0.1"},
+						{TTL: 2, IPAddr: "192.168.1.10"},
+					},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "172.16.0.5", AddressType: "ipv4"}},
+				Port: []Port{
+					{Protocol: "udp", PortID: 53, State: PortState{State: "open|filtered"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    MermaidOutputOptions
+		colors     DotOutputOptions
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:    "Flowchart",
+			options: MermaidOutputOptions{},
+			wantOutput: `flowchart LR
+    classDef open stroke:#228B22,color:#228B22
+    classDef filtered stroke:#FFAE00,color:#FFAE00
+    classDef closed stroke:#DC143C,color:#DC143C
+    classDef other stroke:gray,color:gray
+    n_scanner{{"nmap"}}
+    n_hop10_0_0_1(("10.0.0.1"))
+    n_srv0{{"192.168.1.10<br/>(web.local)"}}
+    n_srv1{{"172.16.0.5"}}
+    n_srv0_port_tcp_80["tcp/80 (open)"]:::open
+    n_srv0 --- n_srv0_port_tcp_80
+    n_srv0_port_tcp_443["tcp/443 (filtered)"]:::filtered
+    n_srv0 --- n_srv0_port_tcp_443
+    n_hop10_0_0_1 --> n_srv0
+    n_scanner --> n_hop10_0_0_1
+    n_srv1_port_udp_53["udp/53 (open|filtered)"]:::other
+    n_srv1 --- n_srv1_port_udp_53
+    n_scanner --> n_srv1
+`,
+		},
+		{
+			name: "Grouped by subnet",
+			options: MermaidOutputOptions{
+				Direction:     "TB",
+				GroupBySubnet: true,
+				SubnetPrefix:  16,
+			},
+			colors: DotOutputOptions{OpenPortColor: "green"},
+			wantOutput: `flowchart TB
+    classDef open stroke:green,color:green
+    classDef filtered stroke:#FFAE00,color:#FFAE00
+    classDef closed stroke:#DC143C,color:#DC143C
+    classDef other stroke:gray,color:gray
+    n_scanner{{"nmap"}}
+    n_hop10_0_0_1(("10.0.0.1"))
+    subgraph n_subnet172_16_0_0_16 ["172.16.0.0/16"]
+        n_srv1{{"172.16.0.5"}}
+    end
+    subgraph n_subnet192_168_0_0_16 ["192.168.0.0/16"]
+        n_srv0{{"192.168.1.10<br/>(web.local)"}}
+    end
+    n_srv0_port_tcp_80["tcp/80 (open)"]:::open
+    n_srv0 --- n_srv0_port_tcp_80
+    n_srv0_port_tcp_443["tcp/443 (filtered)"]:::filtered
+    n_srv0 --- n_srv0_port_tcp_443
+    n_hop10_0_0_1 --> n_srv0
+    n_scanner --> n_hop10_0_0_1
+    n_srv1_port_udp_53["udp/53 (open|filtered)"]:::other
+    n_srv1 --- n_srv1_port_udp_53
+    n_scanner --> n_srv1
+`,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &MermaidFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					MermaidOptions: tt.options,
+					DotOptions:     tt.colors,
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("MermaidFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("MermaidFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func Test_mermaidID(t *testing.T) {
+	tests := []struct {
+		name string
+		v    string
+		want string
+	}{
+		{name: "Keyword", v: "end", want: "n_end"},
+		{name: "IP address", v: "hop10.1.1.1", want: "n_hop10_1_1_1"},
+		{name: "IPv6 address", v: "hop2001:db8::1", want: "n_hop2001_db8__1"},
+		{name: "Only special characters", v: "-->", want: "n_xde7cd317de1b4bc3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mermaidID(tt.v); got != tt.want {
+				t.Errorf("mermaidID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mermaidLabel(t *testing.T) {
+	want := `"a #quot;quoted#quot; #lt;b#gt; #35;1<br/>next"`
+	if got := mermaidLabel("a \"quoted\" <b> #1\nnext"); got != want {
+		t.Errorf("mermaidLabel() = %v, want %v", got, want)
+	}
+}
+
+func TestMermaidOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options MermaidOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: MermaidOutputOptions{}, wantErr: false},
+		{name: "Top to bottom", options: MermaidOutputOptions{Direction: "TB", SubnetPrefix: 16}, wantErr: false},
+		{name: "Wrong direction", options: MermaidOutputOptions{Direction: "XY"}, wantErr: true},
+		{name: "Wrong prefix", options: MermaidOutputOptions{SubnetPrefix: 33}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("MermaidOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_mermaidFlowchart_sharedHops(t *testing.T) {
+	run := &NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.5", AddressType: "ipv4"}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "192.168.1.1"}, {TTL: 2, IPAddr: "10.0.0.5"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.6", AddressType: "ipv4"}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "192.168.1.1"}, {TTL: 2, IPAddr: "10.0.0.6"}}},
+			},
+		},
+	}
+	output := mermaidFlowchart(run, &MermaidOutputOptions{}, &DotOutputOptions{})
+	if count := strings.Count(output, "n_scanner --> n_hop192_168_1_1\n"); count != 1 {
+		t.Errorf("mermaidFlowchart() shared hop edge is written %d times, want 1, output = \n%s", count, output)
+	}
+	for _, want := range []string{"n_hop192_168_1_1 --> n_srv0\n", "n_hop192_168_1_1 --> n_srv1\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("mermaidFlowchart() output does not contain %q, output = \n%s", want, output)
+		}
+	}
+}
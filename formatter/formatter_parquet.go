@@ -0,0 +1,278 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	// ParquetHostsTable is a table that contains one row per host
+	ParquetHostsTable = "hosts"
+	// ParquetPortsTable is a table that contains one row per port (default one)
+	ParquetPortsTable = "ports"
+	// ParquetScriptsTable is a table that contains one row per port script
+	ParquetScriptsTable = "scripts"
+	// ParquetOSMatchesTable is a table that contains one row per OS match
+	ParquetOSMatchesTable = "os_matches"
+)
+
+// ParquetTables is a list of all tables, when directory is provided every table is written to `<table>.parquet` file
+var ParquetTables = []string{ParquetHostsTable, ParquetPortsTable, ParquetScriptsTable, ParquetOSMatchesTable}
+
+// ParquetFormatter is struct defined for Apache Parquet Output use-case
+type ParquetFormatter struct {
+	config *Config
+}
+
+// ParquetScan contains scan metadata columns, they're included in every table
+type ParquetScan struct {
+	ScanID         string    `parquet:"scan_id,dict"`
+	ScanStart      time.Time `parquet:"scan_start,timestamp(millisecond)"`
+	Scanner        string    `parquet:"scanner,dict"`
+	ScannerVersion string    `parquet:"scanner_version,dict"`
+	ScanArgs       string    `parquet:"scan_args,dict"`
+}
+
+// ParquetHost is a row of the hosts table
+type ParquetHost struct {
+	ParquetScan
+	Address       string     `parquet:"address"`
+	Addresses     []string   `parquet:"addresses,list"`
+	MAC           string     `parquet:"mac,optional"`
+	Hostnames     []string   `parquet:"hostnames,list"`
+	State         string     `parquet:"state,dict"`
+	Reason        string     `parquet:"reason,dict"`
+	StartTime     *time.Time `parquet:"start_time,optional,timestamp(millisecond)"`
+	EndTime       *time.Time `parquet:"end_time,optional,timestamp(millisecond)"`
+	Distance      *int32     `parquet:"distance,optional"`
+	UptimeSeconds *int64     `parquet:"uptime_seconds,optional"`
+	OS            string     `parquet:"os,optional"`
+	OpenPorts     int32      `parquet:"open_ports"`
+}
+
+// ParquetPort is a row of the ports table
+type ParquetPort struct {
+	ParquetScan
+	Address   string   `parquet:"address"`
+	Protocol  string   `parquet:"protocol,dict"`
+	Port      int32    `parquet:"port"`
+	State     string   `parquet:"state,dict"`
+	Reason    string   `parquet:"reason,dict"`
+	Service   string   `parquet:"service,dict"`
+	Product   string   `parquet:"product,optional"`
+	Version   string   `parquet:"version,optional"`
+	ExtraInfo string   `parquet:"extra_info,optional"`
+	CPE       []string `parquet:"cpe,list"`
+}
+
+// ParquetScript is a row of the scripts table
+type ParquetScript struct {
+	ParquetScan
+	Address  string `parquet:"address"`
+	Protocol string `parquet:"protocol,dict"`
+	Port     int32  `parquet:"port"`
+	ScriptID string `parquet:"script_id,dict"`
+	Output   string `parquet:"output"`
+}
+
+// ParquetOSMatch is a row of the os_matches table
+type ParquetOSMatch struct {
+	ParquetScan
+	Address  string `parquet:"address"`
+	Name     string `parquet:"name"`
+	Accuracy int32  `parquet:"accuracy"`
+	Line     *int32 `parquet:"line,optional"`
+}
+
+// parquetTables contains rows of all tables
+type parquetTables struct {
+	hosts     []ParquetHost
+	ports     []ParquetPort
+	scripts   []ParquetScript
+	osMatches []ParquetOSMatch
+}
+
+// Format the data to Parquet and output it to appropriate io.Writer, or write all tables
+// to the directory if it's provided
+func (f *ParquetFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.ParquetOptions
+	tables := newParquetTables(&td.NMAPRun, scanIdentifier(&td.OutputOptions))
+	if options.Directory == "" {
+		return tables.write(f.config.Writer, options.table())
+	}
+	for _, table := range ParquetTables {
+		err = tables.writeFile(filepath.Join(options.Directory, table+".parquet"), table)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newParquetTables converts scan results to table rows
+func newParquetTables(n *NMAPRun, scanID string) *parquetTables {
+	scan := ParquetScan{
+		ScanID:         scanID,
+		ScanStart:      time.Unix(int64(n.Start), 0).UTC(),
+		Scanner:        n.Scanner,
+		ScannerVersion: n.Version,
+		ScanArgs:       n.Args,
+	}
+	t := &parquetTables{}
+	for i := range n.Host {
+		host := &n.Host[i]
+		row := ParquetHost{
+			ParquetScan: scan,
+			Addresses:   []string{},
+			Hostnames:   []string{},
+			State:       host.Status.State,
+			Reason:      host.Status.Reason,
+		}
+		for _, a := range host.HostAddress {
+			row.Addresses = append(row.Addresses, a.Address)
+			if a.AddressType == "mac" {
+				row.MAC = a.Address
+			} else if row.Address == "" {
+				row.Address = a.Address
+			}
+		}
+		for _, hostname := range host.HostNames.HostName {
+			row.Hostnames = append(row.Hostnames, hostname.Name)
+		}
+		if host.StartTime != 0 {
+			start := time.Unix(int64(host.StartTime), 0).UTC()
+			row.StartTime = &start
+		}
+		if host.EndTime != 0 {
+			end := time.Unix(int64(host.EndTime), 0).UTC()
+			row.EndTime = &end
+		}
+		if host.Distance.Value != 0 {
+			distance := int32(host.Distance.Value)
+			row.Distance = &distance
+		}
+		if host.Uptime.Seconds != 0 {
+			uptime := int64(host.Uptime.Seconds)
+			row.UptimeSeconds = &uptime
+		}
+		if len(host.OS.OSMatch) > 0 {
+			row.OS = host.OS.OSMatch[0].Name
+		}
+
+		for _, port := range host.Port {
+			if port.State.State == "open" {
+				row.OpenPorts++
+			}
+			cpe := port.Service.CPE
+			if cpe == nil {
+				cpe = []string{}
+			}
+			t.ports = append(t.ports, ParquetPort{
+				ParquetScan: scan,
+				Address:     row.Address,
+				Protocol:    port.Protocol,
+				Port:        int32(port.PortID),
+				State:       port.State.State,
+				Reason:      port.State.Reason,
+				Service:     port.Service.Name,
+				Product:     port.Service.Product,
+				Version:     port.Service.Version,
+				ExtraInfo:   port.Service.ExtraInfo,
+				CPE:         cpe,
+			})
+			for _, script := range port.Script {
+				t.scripts = append(t.scripts, ParquetScript{
+					ParquetScan: scan,
+					Address:     row.Address,
+					Protocol:    port.Protocol,
+					Port:        int32(port.PortID),
+					ScriptID:    script.ID,
+					Output:      script.Output,
+				})
+			}
+		}
+
+		for _, match := range host.OS.OSMatch {
+			accuracy, _ := strconv.Atoi(match.Accuracy)
+			osMatch := ParquetOSMatch{
+				ParquetScan: scan,
+				Address:     row.Address,
+				Name:        match.Name,
+				Accuracy:    int32(accuracy),
+			}
+			if line, err := strconv.Atoi(match.Line); err == nil {
+				l := int32(line)
+				osMatch.Line = &l
+			}
+			t.osMatches = append(t.osMatches, osMatch)
+		}
+		t.hosts = append(t.hosts, row)
+	}
+	return t
+}
+
+// write writes one table to the writer
+func (t *parquetTables) write(w io.Writer, table string) error {
+	options := []parquet.WriterOption{parquet.Compression(&parquet.Snappy), parquet.CreatedBy("nmap-formatter", "", "")}
+	switch table {
+	case ParquetHostsTable:
+		return parquet.Write(w, t.hosts, options...)
+	case ParquetPortsTable:
+		return parquet.Write(w, t.ports, options...)
+	case ParquetScriptsTable:
+		return parquet.Write(w, t.scripts, options...)
+	case ParquetOSMatchesTable:
+		return parquet.Write(w, t.osMatches, options...)
+	}
+	return fmt.Errorf("unknown parquet table: %s", table)
+}
+
+// writeFile writes one table to the file, existing file is overwritten
+func (t *parquetTables) writeFile(path string, table string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = t.write(f, table)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// table returns the table that is written to the output, by default it's ParquetPortsTable
+func (o *ParquetOutputOptions) table() string {
+	if o.Table == "" {
+		return ParquetPortsTable
+	}
+	return o.Table
+}
+
+// Validate checks whether the table is known and the directory exists
+func (o *ParquetOutputOptions) Validate() error {
+	if !slices.Contains(ParquetTables, o.table()) {
+		return fmt.Errorf("unknown parquet table: %s, please choose hosts/ports/scripts/os_matches", o.Table)
+	}
+	if o.Directory != "" {
+		info, err := os.Stat(o.Directory)
+		if err != nil {
+			return fmt.Errorf("could not open parquet directory: %v", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("parquet directory is not a directory: %s", o.Directory)
+		}
+	}
+	return nil
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *ParquetFormatter) defaultTemplateContent() string {
+	return ""
+}
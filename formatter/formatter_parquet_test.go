@@ -0,0 +1,224 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Version: "7.94",
+		Args:    "nmap -sV -O 192.168.1.10",
+		Start:   1700000000,
+		Host: []Host{
+			{
+				StartTime:   1700000001,
+				EndTime:     1700000011,
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up", Reason: "arp-response"},
+				Distance:    Distance{Value: 1},
+				OS: OS{
+					OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4", Accuracy: "98", Line: "67010"}, {Name: "Linux 4.15", Accuracy: "90"}},
+				},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "nginx", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"}},
+						Script:   []Script{{ID: "http-title", Output: "Welcome"}},
+					},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed", Reason: "port-unreach"}, Service: PortService{Name: "domain"}},
+				},
+			},
+		},
+	}
+	scan := ParquetScan{
+		ScanID:         "abc123",
+		ScanStart:      time.Unix(1700000000, 0).UTC(),
+		Scanner:        "nmap",
+		ScannerVersion: "7.94",
+		ScanArgs:       "nmap -sV -O 192.168.1.10",
+	}
+	start := time.Unix(1700000001, 0).UTC()
+	end := time.Unix(1700000011, 0).UTC()
+	distance := int32(1)
+	line := int32(67010)
+	tests := []struct {
+		name    string
+		table   string
+		err     error
+		wantErr bool
+		read    func(r *bytes.Reader) (interface{}, error)
+		want    interface{}
+	}{
+		{
+			name: "Ports table",
+			read: func(r *bytes.Reader) (interface{}, error) { return parquet.Read[ParquetPort](r, r.Size()) },
+			want: []ParquetPort{
+				{
+					ParquetScan: scan, Address: "192.168.1.10", Protocol: "tcp", Port: 80, State: "open", Reason: "syn-ack",
+					Service: "http", Product: "nginx", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"},
+				},
+				{
+					ParquetScan: scan, Address: "192.168.1.10", Protocol: "udp", Port: 53, State: "closed", Reason: "port-unreach",
+					Service: "domain", CPE: []string{},
+				},
+			},
+		},
+		{
+			name:  "Hosts table",
+			table: ParquetHostsTable,
+			read:  func(r *bytes.Reader) (interface{}, error) { return parquet.Read[ParquetHost](r, r.Size()) },
+			want: []ParquetHost{
+				{
+					ParquetScan: scan,
+					Address:     "192.168.1.10",
+					Addresses:   []string{"192.168.1.10", "00:1A:2B:3C:4D:5E"},
+					MAC:         "00:1A:2B:3C:4D:5E",
+					Hostnames:   []string{"web.local"},
+					State:       "up",
+					Reason:      "arp-response",
+					StartTime:   &start,
+					EndTime:     &end,
+					Distance:    &distance,
+					OS:          "Linux 5.0 - 5.4",
+					OpenPorts:   1,
+				},
+			},
+		},
+		{
+			name:  "Scripts table",
+			table: ParquetScriptsTable,
+			read:  func(r *bytes.Reader) (interface{}, error) { return parquet.Read[ParquetScript](r, r.Size()) },
+			want: []ParquetScript{
+				{ParquetScan: scan, Address: "192.168.1.10", Protocol: "tcp", Port: 80, ScriptID: "http-title", Output: "Welcome"},
+			},
+		},
+		{
+			name:  "OS matches table",
+			table: ParquetOSMatchesTable,
+			read:  func(r *bytes.Reader) (interface{}, error) { return parquet.Read[ParquetOSMatch](r, r.Size()) },
+			want: []ParquetOSMatch{
+				{ParquetScan: scan, Address: "192.168.1.10", Name: "Linux 5.0 - 5.4", Accuracy: 98, Line: &line},
+				{ParquetScan: scan, Address: "192.168.1.10", Name: "Linux 4.15", Accuracy: 90},
+			},
+		},
+		{
+			name:    "Unknown table",
+			table:   "services",
+			wantErr: true,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &ParquetFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					ParquetOptions:      ParquetOutputOptions{Table: tt.table},
+					SqliteOutputOptions: SqliteOutputOptions{ScanIdentifier: "abc123"},
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("ParquetFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got, err := tt.read(bytes.NewReader(writer.data))
+			if err != nil {
+				t.Fatalf("could not read parquet output: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParquetFormatter.Format() rows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParquetFormatter_Format_Directory(t *testing.T) {
+	run := NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4"}, {Name: "Linux 4.15"}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, Script: []Script{{ID: "http-title", Output: "Welcome"}}},
+					{Protocol: "udp", PortID: 53},
+				},
+			},
+		},
+	}
+	dir := t.TempDir()
+	writer := &jsonLinesMockedWriter{}
+	f := &ParquetFormatter{config: &Config{Writer: writer}}
+	td := &TemplateData{
+		NMAPRun: run,
+		OutputOptions: OutputOptions{
+			ParquetOptions: ParquetOutputOptions{Directory: dir},
+		},
+	}
+	if err := f.Format(td, ""); err != nil {
+		t.Fatalf("ParquetFormatter.Format() error = %v", err)
+	}
+	if len(writer.data) != 0 {
+		t.Errorf("ParquetFormatter.Format() should not write to the output when directory is provided")
+	}
+	wantRows := map[string]int{ParquetHostsTable: 1, ParquetPortsTable: 2, ParquetScriptsTable: 1, ParquetOSMatchesTable: 2}
+	for table, want := range wantRows {
+		file, err := os.Open(filepath.Join(dir, table+".parquet"))
+		if err != nil {
+			t.Fatalf("could not open %s table: %v", table, err)
+		}
+		info, _ := file.Stat()
+		pf, err := parquet.OpenFile(file, info.Size())
+		if err != nil {
+			t.Fatalf("could not read %s table: %v", table, err)
+		}
+		if pf.NumRows() != int64(want) {
+			t.Errorf("%s table rows = %d, want %d", table, pf.NumRows(), want)
+		}
+		_ = file.Close()
+	}
+}
+
+func TestParquetOutputOptions_Validate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file.parquet")
+	if err := os.WriteFile(file, []byte{}, 0o644); err != nil {
+		t.Fatalf("could not create file: %v", err)
+	}
+	tests := []struct {
+		name    string
+		options ParquetOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: ParquetOutputOptions{}, wantErr: false},
+		{name: "Hosts table in directory", options: ParquetOutputOptions{Table: "hosts", Directory: t.TempDir()}, wantErr: false},
+		{name: "Unknown table", options: ParquetOutputOptions{Table: "services"}, wantErr: true},
+		{name: "Missing directory", options: ParquetOutputOptions{Directory: filepath.Join(t.TempDir(), "missing")}, wantErr: true},
+		{name: "Not a directory", options: ParquetOutputOptions{Directory: file}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("ParquetOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
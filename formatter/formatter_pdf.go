@@ -0,0 +1,469 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	// PDFFontFamily is a default font of PDF document
+	PDFFontFamily = "Helvetica"
+	// PDFMonospaceFontFamily is a font used to display script output and scan arguments
+	PDFMonospaceFontFamily = "Courier"
+
+	// pdfLineHeight is a height of single line in table cells & paragraphs (mm)
+	pdfLineHeight = 5.0
+	// pdfMonospaceLineHeight is a height of single line of monospaced text (mm)
+	pdfMonospaceLineHeight = 4.0
+	// pdfMargin is a page margin on every side (mm)
+	pdfMargin = 15.0
+)
+
+// pdfSummarySection, pdfCustomSection are keys of non-host sections used in the table of contents
+const (
+	pdfSummarySection = "summary"
+	pdfCustomSection  = "custom"
+)
+
+// PDFFormatter is struct defined for PDF Output use-case
+type PDFFormatter struct {
+	config *Config
+}
+
+// pdfDocument holds the state of a single rendering pass of the PDF document
+type pdfDocument struct {
+	pdf     *fpdf.Fpdf
+	tr      func(string) string
+	td      *TemplateData
+	options PDFOutputOptions
+	// tocPages are section page numbers known from the previous rendering pass
+	tocPages map[string]int
+	// pages are section page numbers collected during current rendering pass
+	pages map[string]int
+	// links are internal link IDs of the sections
+	links map[string]int
+}
+
+// pdfSection is an entry of the table of contents
+type pdfSection struct {
+	key   string
+	title string
+}
+
+// Format the data to PDF document and output it to appropriate io.Writer.
+// Document is rendered twice: page numbers of the sections are unknown until they are rendered,
+// so the first pass collects them and the second pass puts them into the table of contents
+func (f *PDFFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	draft := newPDFDocument(td, map[string]int{})
+	err = draft.render()
+	if err != nil {
+		return err
+	}
+	doc := newPDFDocument(td, draft.pages)
+	err = doc.render()
+	if err != nil {
+		return err
+	}
+	return doc.pdf.Output(f.config.Writer)
+}
+
+// newPDFDocument creates new A4 document with section page numbers from previous rendering pass
+func newPDFDocument(td *TemplateData, tocPages map[string]int) *pdfDocument {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCatalogSort(true)
+	// Dates are taken from the scan itself, so the output is the same for the same input
+	scanTime := time.Unix(int64(td.NMAPRun.Start), 0).UTC()
+	pdf.SetCreationDate(scanTime)
+	pdf.SetModificationDate(scanTime)
+	pdf.SetTitle(fmt.Sprintf("NMAP Scan Result: %s", td.NMAPRun.StartStr), true)
+	pdf.SetCreator("nmap-formatter", true)
+
+	doc := &pdfDocument{
+		pdf:      pdf,
+		tr:       pdf.UnicodeTranslatorFromDescriptor(""),
+		td:       td,
+		options:  td.OutputOptions.PDFOptions,
+		tocPages: tocPages,
+		pages:    map[string]int{},
+		links:    map[string]int{},
+	}
+	pdf.SetFooterFunc(doc.footer)
+	return doc
+}
+
+// sections returns all sections that are listed in the table of contents
+func (d *pdfDocument) sections() []pdfSection {
+	sections := []pdfSection{}
+	if !d.options.SkipSummary {
+		sections = append(sections, pdfSection{pdfSummarySection, "Scan Summary"})
+	}
+	if len(d.td.CustomOptions) > 0 {
+		sections = append(sections, pdfSection{pdfCustomSection, "Custom Values"})
+	}
+	for i := range d.td.NMAPRun.Host {
+		sections = append(sections, pdfSection{pdfHostSection(i), markdownHostAnchorTitle(&d.td.NMAPRun.Host[i])})
+	}
+	return sections
+}
+
+// pdfHostSection returns a key of the host section
+func pdfHostSection(index int) string {
+	return fmt.Sprintf("host%d", index)
+}
+
+// render renders the whole document: cover page, TOC, summary, custom values and hosts
+func (d *pdfDocument) render() error {
+	sections := d.sections()
+	for _, s := range sections {
+		d.links[s.key] = d.pdf.AddLink()
+	}
+	if !d.options.SkipHeader {
+		d.cover()
+	}
+	if !d.options.SkipTOC {
+		d.toc(sections)
+	}
+	if !d.options.SkipSummary {
+		d.summary()
+	}
+	if len(d.td.CustomOptions) > 0 {
+		d.customValues()
+	}
+	for i := range d.td.NMAPRun.Host {
+		d.host(i, &d.td.NMAPRun.Host[i])
+	}
+	// Empty document is not a valid PDF
+	if d.pdf.PageNo() == 0 {
+		d.pdf.AddPage()
+	}
+	return d.pdf.Error()
+}
+
+// footer prints page number on every page except the cover page
+func (d *pdfDocument) footer() {
+	if !d.options.SkipHeader && d.pdf.PageNo() == 1 {
+		return
+	}
+	d.pdf.SetY(-pdfMargin + 3)
+	d.font("", 8)
+	d.pdf.CellFormat(0, pdfLineHeight, fmt.Sprintf("Page %d", d.pdf.PageNo()), "", 0, "C", false, 0, "")
+}
+
+// font sets default font with a given style and size
+func (d *pdfDocument) font(style string, size float64) {
+	d.pdf.SetFont(PDFFontFamily, style, size)
+}
+
+// monospace sets monospaced font
+func (d *pdfDocument) monospace() {
+	d.pdf.SetFont(PDFMonospaceFontFamily, "", 8)
+}
+
+// cover renders the cover page with main information about the scan
+func (d *pdfDocument) cover() {
+	n := &d.td.NMAPRun
+	d.pdf.AddPage()
+	d.pdf.SetY(80)
+	d.font("B", 26)
+	d.pdf.MultiCell(0, 12, d.tr("NMAP Scan Result"), "", "C", false)
+	d.font("", 14)
+	d.pdf.MultiCell(0, 8, d.tr(n.StartStr), "", "C", false)
+	d.pdf.Ln(10)
+	d.font("", 11)
+	d.pdf.MultiCell(0, 6, d.tr(fmt.Sprintf("%s %s", n.Scanner, n.Version)), "", "C", false)
+	d.pdf.MultiCell(0, 6, fmt.Sprintf("Hosts up: %d, down: %d, total: %d", n.RunStats.Hosts.Up, n.RunStats.Hosts.Down, n.RunStats.Hosts.Total), "", "C", false)
+	d.pdf.Ln(6)
+	d.monospace()
+	d.pdf.MultiCell(0, pdfMonospaceLineHeight, d.tr(n.Args), "", "C", false)
+}
+
+// toc renders the table of contents with page numbers & internal links
+func (d *pdfDocument) toc(sections []pdfSection) {
+	d.pdf.AddPage()
+	d.heading("Table of Contents", "")
+	d.font("", 10)
+	width := d.contentWidth()
+	for _, s := range sections {
+		page := ""
+		if p, ok := d.tocPages[s.key]; ok {
+			page = fmt.Sprint(p)
+		}
+		d.pdf.CellFormat(width-20, 7, d.tr(s.title), "", 0, "L", false, d.links[s.key], "")
+		d.pdf.CellFormat(20, 7, page, "", 1, "R", false, d.links[s.key], "")
+	}
+}
+
+// summary renders general information about the scan
+func (d *pdfDocument) summary() {
+	n := &d.td.NMAPRun
+	d.pdf.AddPage()
+	d.heading("Scan Summary", pdfSummarySection)
+	d.table([]string{"Name", "Value"}, []float64{0.3, 0.7}, [][]string{
+		{"Scanner", n.Scanner},
+		{"Start time", n.StartStr},
+		{"Finished", n.RunStats.Finished.TimeStr},
+		{"Elapsed", fmt.Sprint(n.RunStats.Finished.Elapsed)},
+		{"Version", n.Version},
+		{"Type of scan", n.ScanInfo.Type},
+		{"Protocol", n.ScanInfo.Protocol},
+		{"Number of services", fmt.Sprint(n.ScanInfo.NumServices)},
+		{"Arguments", n.Args},
+		{"Verbosity", fmt.Sprint(n.Verbose.Level)},
+		{"Debug", fmt.Sprint(n.Debugging.Level)},
+		{"Exit (success)", n.RunStats.Finished.Exit},
+		{"Summary", n.RunStats.Finished.Summary},
+		{"Hosts", fmt.Sprintf("Up: %d, Down: %d, Total: %d", n.RunStats.Hosts.Up, n.RunStats.Hosts.Down, n.RunStats.Hosts.Total)},
+	})
+	d.subheading("Services Scanned")
+	d.preformatted(n.ScanInfo.Services)
+}
+
+// customValues renders custom options passed by the user
+func (d *pdfDocument) customValues() {
+	keys := make([]string, 0, len(d.td.CustomOptions))
+	for k := range d.td.CustomOptions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rows := [][]string{}
+	for _, k := range keys {
+		rows = append(rows, []string{k, d.td.CustomOptions[k]})
+	}
+	d.pdf.AddPage()
+	d.heading("Custom Values", pdfCustomSection)
+	d.table([]string{"Key", "Value"}, []float64{0.3, 0.7}, rows)
+}
+
+// host renders host section: info, ports, traceroute, metrics and port scripts
+func (d *pdfDocument) host(index int, h *Host) {
+	d.pdf.AddPage()
+	d.heading(markdownHostAnchorTitle(h), pdfHostSection(index))
+
+	d.subheading("Info")
+	info := [][]string{
+		{"Address(es)", h.JoinedAddresses("/")},
+		{"Hostnames", pdfValueOrNA(h.JoinedHostNames(" / "))},
+	}
+	for _, used := range h.OS.OSPortUsed {
+		info = append(info, []string{"Used port", fmt.Sprintf("%d/%s (%s)", used.PortID, used.Protocol, used.State)})
+	}
+	for _, match := range h.OS.OSMatch {
+		info = append(info, []string{"OS", fmt.Sprintf("%s (%s%%)", match.Name, match.Accuracy)})
+	}
+	if len(h.OS.OSMatch) == 0 {
+		info = append(info, []string{"OS", "N/A"})
+	}
+	d.table([]string{"Name", "Value"}, []float64{0.3, 0.7}, info)
+
+	d.subheading("Ports")
+	ports := [][]string{}
+	for _, p := range h.Port {
+		ports = append(ports, []string{
+			fmt.Sprint(p.PortID), p.Protocol, p.State.State, p.Service.Name,
+			p.State.Reason, p.Service.Product, p.Service.Version, p.Service.ExtraInfo,
+		})
+	}
+	d.table(
+		[]string{"Port", "Protocol", "State", "Service", "Reason", "Product", "Version", "Extra Info"},
+		[]float64{0.08, 0.1, 0.1, 0.12, 0.12, 0.16, 0.16, 0.16},
+		ports,
+	)
+
+	if !d.options.SkipTraceroute {
+		d.traceroute(h)
+	}
+	if !d.options.SkipMetrics {
+		d.metrics(h)
+	}
+	if !d.options.SkipPortScripts {
+		d.scripts(h)
+	}
+}
+
+// traceroute renders hops from the scanner to the host
+func (d *pdfDocument) traceroute(h *Host) {
+	d.subheading("Traceroute Information")
+	if len(h.Trace.Hops) == 0 {
+		d.paragraph("No traceroute information")
+		return
+	}
+	if h.Trace.Port != 0 {
+		d.paragraph(fmt.Sprintf("Generated traceroute data using %d/%s", h.Trace.Port, h.Trace.Protocol))
+	}
+	hops := [][]string{}
+	for _, hop := range h.Trace.Hops {
+		hops = append(hops, []string{fmt.Sprint(hop.TTL), fmt.Sprint(hop.RTT), hop.IPAddr, hop.Host})
+	}
+	d.table([]string{"Hop", "RTT", "IP", "Host"}, []float64{0.1, 0.15, 0.3, 0.45}, hops)
+}
+
+// metrics renders miscellaneous host metrics
+func (d *pdfDocument) metrics(h *Host) {
+	uptime := "N/A"
+	if h.Uptime.Seconds != 0 {
+		uptime = fmt.Sprintf("%d (last boot: %s)", h.Uptime.Seconds, h.Uptime.LastBoot)
+	}
+	distance := "N/A"
+	if h.Distance.Value != 0 {
+		distance = fmt.Sprint(h.Distance.Value)
+	}
+	d.subheading("Misc Metrics")
+	d.table([]string{"Metric", "Value"}, []float64{0.3, 0.7}, [][]string{
+		{"Ping Results", pdfValueOrNA(h.Status.Reason)},
+		{"System Uptime", uptime},
+		{"Network Distance", distance},
+		{"TCP Sequence Prediction", pdfValueOrNA(strings.TrimSpace(h.TCPSequence.Difficulty + " " + h.TCPSequence.Values))},
+		{"IP ID Sequence Generation", pdfValueOrNA(strings.TrimSpace(h.IPIDSequence.Class + " " + h.IPIDSequence.Values))},
+		{"TCP TS Sequence", pdfValueOrNA(strings.TrimSpace(h.TCPTSSequence.Class + " " + h.TCPTSSequence.Values))},
+	})
+}
+
+// scripts renders output of all port scripts
+func (d *pdfDocument) scripts(h *Host) {
+	hasScripts := false
+	for _, p := range h.Port {
+		if len(p.Script) == 0 {
+			continue
+		}
+		if !hasScripts {
+			d.subheading("Scripts")
+			hasScripts = true
+		}
+		d.font("B", 10)
+		d.pdf.MultiCell(0, pdfLineHeight+1, fmt.Sprintf("Port %d/%s", p.PortID, p.Protocol), "", "L", false)
+		for _, s := range p.Script {
+			d.font("I", 9)
+			d.pdf.MultiCell(0, pdfLineHeight, d.tr(fmt.Sprintf("Script ID: %s", s.ID)), "", "L", false)
+			d.preformatted(strings.Trim(s.Output, "\n"))
+		}
+	}
+}
+
+// heading renders section heading and registers section page, link and bookmark
+func (d *pdfDocument) heading(title string, section string) {
+	if section != "" {
+		d.pages[section] = d.pdf.PageNo()
+		d.pdf.SetLink(d.links[section], -1, -1)
+		d.pdf.Bookmark(d.tr(title), 0, -1)
+	}
+	d.font("B", 16)
+	d.pdf.MultiCell(0, 9, d.tr(title), "B", "L", false)
+	d.pdf.Ln(4)
+}
+
+// subheading renders a title of a host subsection
+func (d *pdfDocument) subheading(title string) {
+	// Subheading should not be left alone at the end of the page
+	if d.pdf.GetY()+4*pdfLineHeight > d.pageBottom() {
+		d.pdf.AddPage()
+	} else {
+		d.pdf.Ln(3)
+	}
+	d.font("B", 12)
+	d.pdf.MultiCell(0, 7, d.tr(title), "", "L", false)
+	d.pdf.Ln(1)
+}
+
+// paragraph renders regular text
+func (d *pdfDocument) paragraph(text string) {
+	d.font("", 9)
+	d.pdf.MultiCell(0, pdfLineHeight, d.tr(text), "", "L", false)
+}
+
+// preformatted renders text with monospaced font on gray background
+func (d *pdfDocument) preformatted(text string) {
+	d.monospace()
+	d.pdf.SetFillColor(240, 240, 240)
+	d.pdf.MultiCell(0, pdfMonospaceLineHeight, d.tr(text), "", "L", true)
+	d.pdf.Ln(2)
+}
+
+// table renders a table, column widths are fractions of the content width. Row is moved to the next
+// page if it does not fit, table header is repeated on every page where table continues
+func (d *pdfDocument) table(header []string, widths []float64, rows [][]string) {
+	contentWidth := d.contentWidth()
+	columns := make([]float64, len(widths))
+	for i := range widths {
+		columns[i] = widths[i] * contentWidth
+	}
+
+	d.tableRow(header, columns, true)
+	for _, row := range rows {
+		if d.pdf.GetY()+d.rowHeight(row, columns, false) > d.pageBottom() {
+			d.pdf.AddPage()
+			d.tableRow(header, columns, true)
+		}
+		d.tableRow(row, columns, false)
+	}
+	d.pdf.Ln(2)
+}
+
+// tableRow renders a single table row, cells are wrapped into multiple lines if needed
+func (d *pdfDocument) tableRow(row []string, columns []float64, isHeader bool) {
+	height := d.rowHeight(row, columns, isHeader)
+	style := "D"
+	if isHeader {
+		style = "FD"
+		d.pdf.SetFillColor(220, 220, 220)
+	}
+	x, y := d.pdf.GetXY()
+	for i := range row {
+		d.pdf.Rect(x, y, columns[i], height, style)
+		d.pdf.SetXY(x, y)
+		// Page break is already handled by the table itself
+		d.pdf.SetAutoPageBreak(false, pdfMargin)
+		d.pdf.MultiCell(columns[i], pdfLineHeight, d.tr(row[i]), "", "L", false)
+		d.pdf.SetAutoPageBreak(true, pdfMargin)
+		x += columns[i]
+	}
+	d.pdf.SetXY(pdfMargin, y+height)
+}
+
+// rowHeight sets the font of the row and calculates its height based on the cell with most lines
+func (d *pdfDocument) rowHeight(row []string, columns []float64, isHeader bool) float64 {
+	if isHeader {
+		d.font("B", 8)
+	} else {
+		d.font("", 8)
+	}
+	lines := 1
+	for i := range row {
+		// Text is already translated to the code page of the font, so it's split byte-wise
+		l := len(d.pdf.SplitLines([]byte(d.tr(row[i])), columns[i]))
+		if l > lines {
+			lines = l
+		}
+	}
+	return float64(lines) * pdfLineHeight
+}
+
+// contentWidth returns page width without margins
+func (d *pdfDocument) contentWidth() float64 {
+	width, _ := d.pdf.GetPageSize()
+	return width - 2*pdfMargin
+}
+
+// pageBottom returns Y position where content area of the page ends
+func (d *pdfDocument) pageBottom() float64 {
+	_, height := d.pdf.GetPageSize()
+	return height - pdfMargin
+}
+
+// pdfValueOrNA returns N/A if value is empty
+func pdfValueOrNA(v string) string {
+	if v == "" {
+		return "N/A"
+	}
+	return v
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *PDFFormatter) defaultTemplateContent() string {
+	return ""
+}
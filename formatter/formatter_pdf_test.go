@@ -0,0 +1,174 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPDFFormatter_Format(t *testing.T) {
+	// The first host has enough ports to span multiple pages
+	ports := []Port{}
+	for i := 0; i < 100; i++ {
+		ports = append(ports, Port{Protocol: "tcp", PortID: 1000 + i, State: PortState{State: "filtered"}, Service: PortService{Name: fmt.Sprintf("service-%d", i)}})
+	}
+	run := NMAPRun{
+		Scanner:  "nmap",
+		Args:     "nmap -sV -p- 192.168.1.0/24",
+		StartStr: "Sat Jan  1 10:00:00 2022",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.1", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "router.local", Type: "PTR"}}},
+				Status:      HostStatus{State: "up"},
+				Trace:       Trace{Port: 80, Protocol: "tcp", Hops: []Hop{{TTL: 1, IPAddr: "192.168.1.1", RTT: 0.5}}},
+				Port:        ports,
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   22,
+						State:    PortState{State: "open"},
+						// Text outside of Latin-1 and multiline script output
+						Service: PortService{Name: "ssh", Product: "OpenSSH – Ubuntu"},
+						Script:  []Script{{ID: "ssh-hostkey", Output: "\n  2048 aa:bb:cc (RSA)\n"}},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		td      *TemplateData
+		err     error
+		wantErr bool
+	}{
+		{
+			name: "Full report",
+			td: &TemplateData{
+				NMAPRun:       run,
+				CustomOptions: map[string]string{"client": "ACME Corp"},
+			},
+		},
+		{
+			name: "Empty report",
+			td: &TemplateData{
+				OutputOptions: OutputOptions{
+					PDFOptions: PDFOutputOptions{
+						SkipHeader:  true,
+						SkipTOC:     true,
+						SkipSummary: true,
+					},
+				},
+			},
+		},
+		{
+			name:    "Error",
+			td:      &TemplateData{NMAPRun: run},
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &PDFFormatter{config: &Config{Writer: writer}}
+			if err := f.Format(tt.td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("PDFFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err != nil {
+				return
+			}
+			if !bytes.HasPrefix(writer.data, []byte("%PDF-")) {
+				t.Errorf("PDFFormatter.Format() output is not a PDF document")
+			}
+
+			// The same input should always result in the same document
+			writerAgain := &jsonLinesMockedWriter{}
+			f = &PDFFormatter{config: &Config{Writer: writerAgain}}
+			if err := f.Format(tt.td, ""); err != nil {
+				t.Errorf("PDFFormatter.Format() error = %v", err)
+			}
+			if !bytes.Equal(writer.data, writerAgain.data) {
+				t.Errorf("PDFFormatter.Format() output is not deterministic")
+			}
+		})
+	}
+}
+
+func TestPDFDocument_renderPages(t *testing.T) {
+	tests := []struct {
+		name string
+		td   *TemplateData
+		// ports is a number of ports of the first host
+		ports     int
+		wantPages map[string]int
+	}{
+		{
+			name:  "Cover, TOC, summary and hosts",
+			td:    &TemplateData{},
+			ports: 5,
+			wantPages: map[string]int{
+				pdfSummarySection: 3,
+				"host0":           4,
+				"host1":           5,
+			},
+		},
+		{
+			name: "Port table continues on the next pages",
+			td: &TemplateData{
+				CustomOptions: map[string]string{"key": "value"},
+			},
+			ports: 100,
+			wantPages: map[string]int{
+				pdfSummarySection: 3,
+				pdfCustomSection:  4,
+				"host0":           5,
+				"host1":           8,
+			},
+		},
+		{
+			name: "Skipped cover, TOC and summary",
+			td: &TemplateData{
+				OutputOptions: OutputOptions{
+					PDFOptions: PDFOutputOptions{
+						SkipHeader:  true,
+						SkipTOC:     true,
+						SkipSummary: true,
+					},
+				},
+			},
+			ports: 5,
+			wantPages: map[string]int{
+				"host0": 1,
+				"host1": 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ports := []Port{}
+			for i := 0; i < tt.ports; i++ {
+				ports = append(ports, Port{Protocol: "tcp", PortID: 1000 + i, State: PortState{State: "filtered"}})
+			}
+			tt.td.NMAPRun = NMAPRun{
+				Host: []Host{
+					{HostAddress: []HostAddress{{Address: "192.168.1.1", AddressType: "ipv4"}}, Status: HostStatus{State: "up"}, Port: ports},
+					{HostAddress: []HostAddress{{Address: "192.168.1.2", AddressType: "ipv4"}}, Status: HostStatus{State: "up"}},
+				},
+			}
+			d := newPDFDocument(tt.td, map[string]int{})
+			if err := d.render(); err != nil {
+				t.Errorf("pdfDocument.render() error = %v", err)
+			}
+			if !reflect.DeepEqual(d.pages, tt.wantPages) {
+				t.Errorf("pdfDocument.render() pages = %v, want %v", d.pages, tt.wantPages)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusDefaultHostLabels is a default list of labels that identify the host in per-host metrics
+var PrometheusDefaultHostLabels = []string{"address", "hostname"}
+
+// prometheusHostLabels contains all labels that can be used to identify the host
+var prometheusHostLabels = map[string]func(h *Host) string{
+	"address": func(h *Host) string {
+		var addresses []string
+		for _, a := range h.HostAddress {
+			if a.AddressType != "mac" {
+				addresses = append(addresses, a.Address)
+			}
+		}
+		return strings.Join(addresses, ",")
+	},
+	"hostname": func(h *Host) string { return h.JoinedHostNames(",") },
+	"mac": func(h *Host) string {
+		for _, a := range h.HostAddress {
+			if a.AddressType == "mac" {
+				return a.Address
+			}
+		}
+		return ""
+	},
+	"os": func(h *Host) string {
+		if len(h.OS.OSMatch) > 0 {
+			return h.OS.OSMatch[0].Name
+		}
+		return ""
+	},
+}
+
+// prometheusLabelName is a valid label name according to Prometheus data model
+var prometheusLabelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// PrometheusFormatter is struct defined for Prometheus exposition format Output use-case,
+// output file is written atomically, so it can be used with node_exporter textfile collector
+type PrometheusFormatter struct {
+	config *Config
+}
+
+// prometheusLabel is a name-value pair of the metric label
+type prometheusLabel struct {
+	name  string
+	value string
+}
+
+// prometheusWriter writes metrics, constant labels are prepended to labels of every sample
+type prometheusWriter struct {
+	b      *strings.Builder
+	labels []prometheusLabel
+}
+
+// Format the data to Prometheus metrics and output it to appropriate io.Writer
+func (f *PrometheusFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	metrics, err := prometheusMetrics(&td.NMAPRun, &td.OutputOptions.PrometheusOptions)
+	if err != nil {
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte(metrics))
+	return
+}
+
+// prometheusMetrics returns scan, host and service metrics in text exposition format
+func prometheusMetrics(n *NMAPRun, options *PrometheusOutputOptions) (string, error) {
+	hostLabels := options.HostLabels
+	if len(hostLabels) == 0 {
+		hostLabels = PrometheusDefaultHostLabels
+	}
+	for _, name := range hostLabels {
+		if _, ok := prometheusHostLabels[name]; !ok {
+			return "", fmt.Errorf("unknown prometheus host label: %s", name)
+		}
+	}
+
+	w := &prometheusWriter{b: &strings.Builder{}}
+	keys := make([]string, 0, len(options.Labels))
+	for k := range options.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w.labels = append(w.labels, prometheusLabel{k, options.Labels[k]})
+	}
+
+	w.metric("nmap_scan_info", "Information about the scan, value is always 1")
+	w.sample("nmap_scan_info", 1, prometheusLabel{"scanner", n.Scanner}, prometheusLabel{"version", n.Version}, prometheusLabel{"args", n.Args})
+	w.metric("nmap_scan_start_timestamp_seconds", "Time when the scan was started")
+	w.sample("nmap_scan_start_timestamp_seconds", float64(n.Start))
+	w.metric("nmap_scan_end_timestamp_seconds", "Time when the scan was finished")
+	w.sample("nmap_scan_end_timestamp_seconds", float64(n.RunStats.Finished.Time))
+	w.metric("nmap_scan_duration_seconds", "Scan duration")
+	w.sample("nmap_scan_duration_seconds", n.RunStats.Finished.Elapsed)
+	w.metric("nmap_hosts", "Amount of scanned hosts by state")
+	w.sample("nmap_hosts", float64(n.RunStats.Hosts.Up), prometheusLabel{"state", "up"})
+	w.sample("nmap_hosts", float64(n.RunStats.Hosts.Down), prometheusLabel{"state", "down"})
+
+	w.metric("nmap_host_up", "Whether the host is up (1) or not (0)")
+	for i := range n.Host {
+		up := 0.0
+		if n.Host[i].Status.State == "up" {
+			up = 1
+		}
+		w.sample("nmap_host_up", up, prometheusHostLabelValues(&n.Host[i], hostLabels)...)
+	}
+
+	type service struct {
+		name     string
+		protocol string
+	}
+	services := map[service]int{}
+	w.metric("nmap_host_open_ports", "Amount of open ports on the host")
+	for i := range n.Host {
+		open := 0
+		for _, p := range n.Host[i].Port {
+			if p.State.State != "open" {
+				continue
+			}
+			open++
+			services[service{p.Service.Name, p.Protocol}]++
+		}
+		w.sample("nmap_host_open_ports", float64(open), prometheusHostLabelValues(&n.Host[i], hostLabels)...)
+	}
+
+	serviceKeys := make([]service, 0, len(services))
+	for s := range services {
+		serviceKeys = append(serviceKeys, s)
+	}
+	sort.Slice(serviceKeys, func(i, j int) bool {
+		if serviceKeys[i].name == serviceKeys[j].name {
+			return serviceKeys[i].protocol < serviceKeys[j].protocol
+		}
+		return serviceKeys[i].name < serviceKeys[j].name
+	})
+	w.metric("nmap_service_open_ports", "Amount of open ports by service")
+	for _, s := range serviceKeys {
+		w.sample("nmap_service_open_ports", float64(services[s]), prometheusLabel{"service", s.name}, prometheusLabel{"protocol", s.protocol})
+	}
+	return w.b.String(), nil
+}
+
+// prometheusHostLabelValues returns labels that identify the host
+func prometheusHostLabelValues(h *Host, names []string) []prometheusLabel {
+	labels := make([]prometheusLabel, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, prometheusLabel{name, prometheusHostLabels[name](h)})
+	}
+	return labels
+}
+
+// metric writes HELP and TYPE lines, all metrics are gauges
+func (w *prometheusWriter) metric(name string, help string) {
+	fmt.Fprintf(w.b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w.b, "# TYPE %s gauge\n", name)
+}
+
+// sample writes metric value with constant labels followed by sample labels
+func (w *prometheusWriter) sample(name string, value float64, labels ...prometheusLabel) {
+	w.b.WriteString(name)
+	all := append(append([]prometheusLabel{}, w.labels...), labels...)
+	if len(all) > 0 {
+		pairs := make([]string, len(all))
+		for i, l := range all {
+			pairs[i] = fmt.Sprintf(`%s="%s"`, l.name, prometheusEscape(l.value))
+		}
+		fmt.Fprintf(w.b, "{%s}", strings.Join(pairs, ","))
+	}
+	fmt.Fprintf(w.b, " %s\n", strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// prometheusEscape escapes label value: backslash, double-quote and line feed
+func prometheusEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// Validate checks whether constant label names are valid and host labels are known,
+// host labels have to contain address, otherwise series of different hosts may collide
+// (e.g. hosts without hostname) and textfile collector rejects the whole file
+func (o *PrometheusOutputOptions) Validate() error {
+	seen := map[string]bool{}
+	for _, name := range o.HostLabels {
+		if _, ok := prometheusHostLabels[name]; !ok {
+			return fmt.Errorf("unknown prometheus host label: %s, please choose address/hostname/mac/os", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("prometheus host label is duplicated: %s", name)
+		}
+		seen[name] = true
+	}
+	if len(o.HostLabels) > 0 && !seen["address"] {
+		return fmt.Errorf("prometheus host labels should contain address to identify the host: %s", strings.Join(o.HostLabels, ","))
+	}
+	reserved := map[string]bool{"scanner": true, "version": true, "args": true, "state": true, "service": true, "protocol": true}
+	for name := range prometheusHostLabels {
+		reserved[name] = true
+	}
+	for name := range o.Labels {
+		if !prometheusLabelName.MatchString(name) || strings.HasPrefix(name, "__") {
+			return fmt.Errorf("not valid prometheus label name: %s", name)
+		}
+		if reserved[name] {
+			return fmt.Errorf("prometheus label name is already used by metrics: %s", name)
+		}
+	}
+	return nil
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *PrometheusFormatter) defaultTemplateContent() string {
+	return ""
+}
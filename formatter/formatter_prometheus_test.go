@@ -0,0 +1,198 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Version: "7.94",
+		Args:    `nmap -sV --script "http-*" 192.168.1.0/24`,
+		Start:   1700000000,
+		RunStats: RunStats{
+			Finished: Finished{Time: 1700000012, Elapsed: 12.35},
+			Hosts:    StatHosts{Up: 2, Down: 1},
+		},
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up"},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: PortService{Name: "http"}},
+					{Protocol: "tcp", PortID: 22, State: PortState{State: "open"}, Service: PortService{Name: "ssh"}},
+					{Protocol: "tcp", PortID: 443, State: PortState{State: "closed"}, Service: PortService{Name: "https"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.20", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 8080, State: PortState{State: "open"}, Service: PortService{Name: "http"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    PrometheusOutputOptions
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Default labels",
+			wantOutput: `# HELP nmap_scan_info Information about the scan, value is always 1
+# TYPE nmap_scan_info gauge
+nmap_scan_info{scanner="nmap",version="7.94",args="nmap -sV --script \"http-*\" 192.168.1.0/24"} 1
+# HELP nmap_scan_start_timestamp_seconds Time when the scan was started
+# TYPE nmap_scan_start_timestamp_seconds gauge
+nmap_scan_start_timestamp_seconds 1700000000
+# HELP nmap_scan_end_timestamp_seconds Time when the scan was finished
+# TYPE nmap_scan_end_timestamp_seconds gauge
+nmap_scan_end_timestamp_seconds 1700000012
+# HELP nmap_scan_duration_seconds Scan duration
+# TYPE nmap_scan_duration_seconds gauge
+nmap_scan_duration_seconds 12.35
+# HELP nmap_hosts Amount of scanned hosts by state
+# TYPE nmap_hosts gauge
+nmap_hosts{state="up"} 2
+nmap_hosts{state="down"} 1
+# HELP nmap_host_up Whether the host is up (1) or not (0)
+# TYPE nmap_host_up gauge
+nmap_host_up{address="192.168.1.10",hostname="web.local"} 1
+nmap_host_up{address="192.168.1.20",hostname=""} 1
+# HELP nmap_host_open_ports Amount of open ports on the host
+# TYPE nmap_host_open_ports gauge
+nmap_host_open_ports{address="192.168.1.10",hostname="web.local"} 2
+nmap_host_open_ports{address="192.168.1.20",hostname=""} 1
+# HELP nmap_service_open_ports Amount of open ports by service
+# TYPE nmap_service_open_ports gauge
+nmap_service_open_ports{service="http",protocol="tcp"} 2
+nmap_service_open_ports{service="ssh",protocol="tcp"} 1
+`,
+		},
+		{
+			name: "Custom labels",
+			options: PrometheusOutputOptions{
+				Labels:     map[string]string{"site": "dc1", "job": "nmap"},
+				HostLabels: []string{"mac"},
+			},
+			wantOutput: `# HELP nmap_scan_info Information about the scan, value is always 1
+# TYPE nmap_scan_info gauge
+nmap_scan_info{job="nmap",site="dc1",scanner="nmap",version="7.94",args="nmap -sV --script \"http-*\" 192.168.1.0/24"} 1
+# HELP nmap_scan_start_timestamp_seconds Time when the scan was started
+# TYPE nmap_scan_start_timestamp_seconds gauge
+nmap_scan_start_timestamp_seconds{job="nmap",site="dc1"} 1700000000
+# HELP nmap_scan_end_timestamp_seconds Time when the scan was finished
+# TYPE nmap_scan_end_timestamp_seconds gauge
+nmap_scan_end_timestamp_seconds{job="nmap",site="dc1"} 1700000012
+# HELP nmap_scan_duration_seconds Scan duration
+# TYPE nmap_scan_duration_seconds gauge
+nmap_scan_duration_seconds{job="nmap",site="dc1"} 12.35
+# HELP nmap_hosts Amount of scanned hosts by state
+# TYPE nmap_hosts gauge
+nmap_hosts{job="nmap",site="dc1",state="up"} 2
+nmap_hosts{job="nmap",site="dc1",state="down"} 1
+# HELP nmap_host_up Whether the host is up (1) or not (0)
+# TYPE nmap_host_up gauge
+nmap_host_up{job="nmap",site="dc1",mac="00:1A:2B:3C:4D:5E"} 1
+nmap_host_up{job="nmap",site="dc1",mac=""} 1
+# HELP nmap_host_open_ports Amount of open ports on the host
+# TYPE nmap_host_open_ports gauge
+nmap_host_open_ports{job="nmap",site="dc1",mac="00:1A:2B:3C:4D:5E"} 2
+nmap_host_open_ports{job="nmap",site="dc1",mac=""} 1
+# HELP nmap_service_open_ports Amount of open ports by service
+# TYPE nmap_service_open_ports gauge
+nmap_service_open_ports{job="nmap",site="dc1",service="http",protocol="tcp"} 2
+nmap_service_open_ports{job="nmap",site="dc1",service="ssh",protocol="tcp"} 1
+`,
+		},
+		{
+			name:    "Unknown host label",
+			options: PrometheusOutputOptions{HostLabels: []string{"address", "uptime"}},
+			wantErr: true,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &PrometheusFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					PrometheusOptions: tt.options,
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("PrometheusFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("PrometheusFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestPrometheusOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options PrometheusOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: PrometheusOutputOptions{}, wantErr: false},
+		{name: "Custom labels", options: PrometheusOutputOptions{Labels: map[string]string{"site": "dc1"}, HostLabels: []string{"address", "os"}}, wantErr: false},
+		{name: "Unknown host label", options: PrometheusOutputOptions{HostLabels: []string{"uptime"}}, wantErr: true},
+		{name: "Host labels without address", options: PrometheusOutputOptions{HostLabels: []string{"hostname"}}, wantErr: true},
+		{name: "Duplicated host label", options: PrometheusOutputOptions{HostLabels: []string{"address", "address"}}, wantErr: true},
+		{name: "Wrong label name", options: PrometheusOutputOptions{Labels: map[string]string{"data-center": "dc1"}}, wantErr: true},
+		{name: "Reserved label name", options: PrometheusOutputOptions{Labels: map[string]string{"__name__": "x"}}, wantErr: true},
+		{name: "Label name used by metrics", options: PrometheusOutputOptions{Labels: map[string]string{"service": "x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PrometheusOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_prometheusMetrics_uniqueHostSeries(t *testing.T) {
+	run := &NMAPRun{
+		Host: []Host{
+			{HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}}, Status: HostStatus{State: "up"}},
+			{HostAddress: []HostAddress{{Address: "10.0.0.2", AddressType: "ipv4"}}, Status: HostStatus{State: "up"}},
+		},
+	}
+	options := &PrometheusOutputOptions{HostLabels: PrometheusDefaultHostLabels}
+	if err := options.Validate(); err != nil {
+		t.Fatalf("PrometheusOutputOptions.Validate() error = %v", err)
+	}
+	metrics, err := prometheusMetrics(run, options)
+	if err != nil {
+		t.Fatalf("prometheusMetrics() error = %v", err)
+	}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(metrics, "\n") {
+		if !strings.HasPrefix(line, "nmap_host_") {
+			continue
+		}
+		series := line[:strings.LastIndex(line, " ")]
+		if seen[series] {
+			t.Errorf("prometheusMetrics() series is duplicated: %s", series)
+		}
+		seen[series] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("prometheusMetrics() host series = %d, want 4", len(seen))
+	}
+}
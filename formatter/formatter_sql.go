@@ -0,0 +1,414 @@
+package formatter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SQLPostgresDialect is a PostgreSQL dialect (default one), script can be loaded with `psql -f`
+	SQLPostgresDialect = "postgres"
+	// SQLMySQLDialect is a MySQL (MariaDB) dialect, script can be loaded with `mysql < dump.sql`
+	SQLMySQLDialect = "mysql"
+	// SQLSqliteDialect is a SQLite dialect, script can be loaded with `sqlite3 nmap.sqlite < dump.sql`
+	SQLSqliteDialect = "sqlite"
+)
+
+// sqlRaw is an SQL expression that is written to the statement as is, without quoting
+type sqlRaw string
+
+var (
+	sqlCreateTableRegexp = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS (\w+) \($`)
+	sqlColumnRegexp      = regexp.MustCompile(`^(\w+) (\w+)( not null)?( primary key)?,?$`)
+	sqlCreateIndexRegexp = regexp.MustCompile(`^CREATE INDEX IF NOT EXISTS (\w+) ON (\w+)\((\w+)\);$`)
+)
+
+// SQLFormatter is struct defined for SQL dump Output use-case
+type SQLFormatter struct {
+	config *Config
+}
+
+// sqlTable is a table definition parsed from SqliteDDL
+type sqlTable struct {
+	name    string
+	columns []sqlColumn
+	indexes []sqlIndex
+}
+
+// sqlColumn is a column definition, type is a SQLite type (integer, real, text)
+type sqlColumn struct {
+	name       string
+	typ        string
+	notNull    bool
+	primaryKey bool
+	indexed    bool
+}
+
+// sqlIndex is a single column index definition
+type sqlIndex struct {
+	name   string
+	column string
+}
+
+// sqlDump builds DDL and INSERT script for the dialect
+type sqlDump struct {
+	dialect string
+	b       strings.Builder
+}
+
+// Format the data to SQL script (DDL + INSERT statements) and output it to appropriate io.Writer
+func (f *SQLFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	tables, err := parseSqliteDDL(SqliteDDL)
+	if err != nil {
+		return err
+	}
+	d := &sqlDump{dialect: td.OutputOptions.SQLOptions.dialect()}
+	fmt.Fprintf(&d.b, "-- nmap-formatter %s SQL dump (%s dialect)\n\n", f.config.CurrentVersion, d.dialect)
+	d.createTables(tables)
+	d.b.WriteString("\n" + d.begin())
+	d.b.WriteString("DELETE FROM nf_schema;\n")
+	d.insert("INSERT INTO nf_schema (version) VALUES (?)", f.config.CurrentVersion)
+	d.insertScan(&td.NMAPRun, scanIdentifier(&td.OutputOptions), time.Now())
+	d.b.WriteString(d.commit())
+	_, err = f.config.Writer.Write([]byte(d.b.String()))
+	return err
+}
+
+// parseSqliteDDL parses table and index definitions from SQLite schema, so the same
+// table layout is used in every dialect
+func parseSqliteDDL(ddl string) ([]*sqlTable, error) {
+	var tables []*sqlTable
+	var current *sqlTable
+	for _, line := range strings.Split(ddl, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+		if m := sqlCreateTableRegexp.FindStringSubmatch(line); m != nil {
+			current = &sqlTable{name: m[1]}
+			tables = append(tables, current)
+			continue
+		}
+		if m := sqlCreateIndexRegexp.FindStringSubmatch(line); m != nil {
+			table := findSQLTable(tables, m[2])
+			if table == nil {
+				return nil, fmt.Errorf("index %s is defined on unknown table: %s", m[1], m[2])
+			}
+			table.addIndex(sqlIndex{name: m[1], column: m[3]})
+			continue
+		}
+		if line == ");" {
+			current = nil
+			continue
+		}
+		m := sqlColumnRegexp.FindStringSubmatch(line)
+		if m == nil || current == nil {
+			return nil, fmt.Errorf("could not parse sqlite schema line: %s", line)
+		}
+		current.columns = append(current.columns, sqlColumn{
+			name:       m[1],
+			typ:        m[2],
+			notNull:    m[3] != "",
+			primaryKey: m[4] != "",
+		})
+	}
+	return tables, nil
+}
+
+// findSQLTable returns the table by name or nil if it does not exist
+func findSQLTable(tables []*sqlTable, name string) *sqlTable {
+	for _, t := range tables {
+		if t.name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// addIndex adds the index and marks the column as indexed
+func (t *sqlTable) addIndex(index sqlIndex) {
+	t.indexes = append(t.indexes, index)
+	for i := range t.columns {
+		if t.columns[i].name == index.column {
+			t.columns[i].indexed = true
+		}
+	}
+}
+
+// createTables writes CREATE TABLE and CREATE INDEX statements, MySQL does not support
+// `CREATE INDEX IF NOT EXISTS`, so indexes are defined inside of the table instead
+func (d *sqlDump) createTables(tables []*sqlTable) {
+	for _, t := range tables {
+		definitions := make([]string, 0, len(t.columns)+len(t.indexes))
+		for _, c := range t.columns {
+			definitions = append(definitions, c.name+" "+d.columnType(&c))
+		}
+		if d.dialect == SQLMySQLDialect {
+			for _, index := range t.indexes {
+				definitions = append(definitions, fmt.Sprintf("INDEX %s (%s)", index.name, index.column))
+			}
+		}
+		fmt.Fprintf(&d.b, "CREATE TABLE IF NOT EXISTS %s (\n\t%s\n);\n", t.name, strings.Join(definitions, ",\n\t"))
+	}
+	if d.dialect == SQLMySQLDialect {
+		return
+	}
+	for _, t := range tables {
+		for _, index := range t.indexes {
+			fmt.Fprintf(&d.b, "CREATE INDEX IF NOT EXISTS %s ON %s (%s);\n", index.name, t.name, index.column)
+		}
+	}
+}
+
+// columnType converts SQLite column type to the dialect one, MySQL can't index
+// text columns without prefix length, so indexed text columns are varchar
+func (d *sqlDump) columnType(c *sqlColumn) string {
+	if c.primaryKey {
+		switch d.dialect {
+		case SQLPostgresDialect:
+			return "bigserial primary key"
+		case SQLMySQLDialect:
+			return "bigint not null auto_increment primary key"
+		}
+		return "integer not null primary key"
+	}
+	typ := c.typ
+	if d.dialect != SQLSqliteDialect {
+		switch c.typ {
+		case "integer":
+			typ = "bigint"
+		case "real":
+			typ = "double precision"
+		default:
+			typ = "text"
+			if d.dialect == SQLMySQLDialect && c.indexed {
+				typ = "varchar(255)"
+			}
+		}
+	}
+	if c.notNull {
+		typ += " not null"
+	}
+	return typ
+}
+
+// begin returns statements that start the transaction, MySQL script enables NO_BACKSLASH_ESCAPES
+// mode, so string literals are the same regardless of server settings. SQLite has no session
+// variables, so IDs of parent rows are saved to the temporary table
+func (d *sqlDump) begin() string {
+	switch d.dialect {
+	case SQLMySQLDialect:
+		return "SET @nf_sql_mode = @@SESSION.sql_mode;\n" +
+			"SET SESSION sql_mode = CONCAT_WS(',', NULLIF(@@SESSION.sql_mode, ''), 'NO_BACKSLASH_ESCAPES');\n" +
+			"START TRANSACTION;\n"
+	case SQLSqliteDialect:
+		return "BEGIN TRANSACTION;\nCREATE TEMP TABLE IF NOT EXISTS nf_ids (name text primary key, id integer);\n"
+	}
+	return "BEGIN;\n"
+}
+
+// commit returns statements that finish the transaction, MySQL mode of the session is restored
+func (d *sqlDump) commit() string {
+	if d.dialect == SQLMySQLDialect {
+		return "COMMIT;\nSET SESSION sql_mode = @nf_sql_mode;\n"
+	}
+	return "COMMIT;\n"
+}
+
+// saveID saves ID of the row that was just inserted into the table, so the following rows can
+// reference it with savedID. IDs are session specific, so concurrent writes do not affect them
+func (d *sqlDump) saveID(table string) {
+	switch d.dialect {
+	case SQLMySQLDialect:
+		fmt.Fprintf(&d.b, "SET @nf_%s_id = LAST_INSERT_ID();\n", table)
+	case SQLSqliteDialect:
+		fmt.Fprintf(&d.b, "INSERT OR REPLACE INTO temp.nf_ids (name, id) VALUES ('%s', last_insert_rowid());\n", table)
+	}
+}
+
+// savedID returns an expression of the last inserted ID of the table, PostgreSQL keeps
+// the last value of the sequence in the session, so it's used directly
+func (d *sqlDump) savedID(table string) sqlRaw {
+	switch d.dialect {
+	case SQLMySQLDialect:
+		return sqlRaw("@nf_" + table + "_id")
+	case SQLSqliteDialect:
+		return sqlRaw("(SELECT id FROM temp.nf_ids WHERE name = '" + table + "')")
+	}
+	return sqlRaw("currval(pg_get_serial_sequence('" + table + "', 'id'))")
+}
+
+// insertScan writes INSERT statements in the same order as ScanRepository populates sqlite database
+func (d *sqlDump) insertScan(n *NMAPRun, scanID string, now time.Time) {
+	d.insert(
+		insertScanSQL,
+		scanID,
+		n.Scanner,
+		n.Args,
+		n.ScanInfo.Type,
+		n.ScanInfo.Protocol,
+		n.ScanInfo.NumServices,
+		n.ScanInfo.Services,
+		n.RunStats.Finished.Time,
+		n.RunStats.Finished.TimeStr,
+		n.RunStats.Finished.Elapsed,
+		n.RunStats.Finished.Summary,
+		n.RunStats.Finished.Exit,
+		n.RunStats.Hosts.Up,
+		n.RunStats.Hosts.Down,
+		n.RunStats.Hosts.Total,
+		n.Verbose.Level,
+		n.Debugging.Level,
+		n.Start,
+		n.StartStr,
+		now.Unix(),
+	)
+	d.saveID("scans")
+	for i := range n.Host {
+		d.insertHost(&n.Host[i])
+	}
+}
+
+// insertHost writes INSERT statements of the host and all related rows
+func (d *sqlDump) insertHost(host *Host) {
+	d.insert(
+		insertHostsSQL,
+		d.savedID("scans"),
+		host.JoinedAddresses(sqliteStringDelimiter),
+		host.JoinedHostNames(sqliteStringDelimiter),
+		host.StartTime,
+		host.EndTime,
+		host.Status.State,
+		host.Status.Reason,
+		host.Uptime.Seconds,
+		host.Uptime.LastBoot,
+		host.Distance.Value,
+		host.TCPSequence.Index,
+		host.TCPSequence.Difficulty,
+		host.TCPSequence.Values,
+		host.IPIDSequence.Class,
+		host.IPIDSequence.Values,
+		host.TCPTSSequence.Class,
+		host.TCPTSSequence.Values,
+		host.Trace.Port,
+		host.Trace.Protocol,
+		host.Status.State,
+	)
+	d.saveID("hosts")
+	hostID := d.savedID("hosts")
+	for _, hop := range host.Trace.Hops {
+		d.insert(insertHostTracesHopsSQL, hostID, hop.TTL, hop.IPAddr, hop.RTT, hop.Host)
+	}
+	for _, addr := range host.HostAddress {
+		d.insert(insertHostAddressesSQL, hostID, addr.Address, addr.AddressType)
+	}
+	for _, name := range host.HostNames.HostName {
+		d.insert(insertHostNamesSQL, hostID, name.Name, name.Type)
+	}
+	for _, class := range host.OS.OSClass {
+		d.insert(
+			insertHostOSClassSQL,
+			hostID,
+			class.Type,
+			class.Vendor,
+			class.OSFamily,
+			class.OSGen,
+			class.Accuracy,
+			strings.Join(class.CPE, sqliteStringDelimiter),
+		)
+	}
+	for _, port := range host.OS.OSPortUsed {
+		d.insert(insertHostOSPortUsedSQL, hostID, port.State, port.Protocol, port.PortID)
+	}
+	for _, match := range host.OS.OSMatch {
+		d.insert(insertHostOSMatchSQL, hostID, match.Name, match.Accuracy, match.Line)
+	}
+	for _, port := range host.Port {
+		d.insert(
+			insertPortsSQL,
+			hostID,
+			port.PortID,
+			port.State.State,
+			port.State.Reason,
+			port.State.ReasonTTL,
+			port.Service.Name,
+			port.Service.Product,
+			port.Service.Version,
+			port.Service.ExtraInfo,
+			port.Service.Method,
+			port.Service.Conf,
+			strings.Join(port.Service.CPE, sqliteStringDelimiter),
+		)
+		if len(port.Script) == 0 {
+			continue
+		}
+		d.saveID("ports")
+		for _, script := range port.Script {
+			d.insert(insertPortsScriptsSQL, d.savedID("ports"), script.ID, script.Output)
+		}
+	}
+}
+
+// insert writes the statement on a single line, replacing `?` placeholders with literals
+func (d *sqlDump) insert(query string, args ...any) {
+	query = strings.Join(strings.Fields(query), " ")
+	query = strings.NewReplacer("( ", "(", " )", ")").Replace(query)
+	parts := strings.Split(query, "?")
+	for i, part := range parts {
+		d.b.WriteString(part)
+		if i < len(args) && i < len(parts)-1 {
+			d.b.WriteString(d.literal(args[i]))
+		}
+	}
+	d.b.WriteString(";\n")
+}
+
+// literal converts the value to SQL literal of the dialect
+func (d *sqlDump) literal(v any) string {
+	switch v := v.(type) {
+	case sqlRaw:
+		return string(v)
+	case string:
+		return d.quote(v)
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+	return d.quote(fmt.Sprint(v))
+}
+
+// quote returns quoted string literal, backslash is not an escape character in any dialect
+// (MySQL script enables NO_BACKSLASH_ESCAPES mode)
+func (d *sqlDump) quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// dialect returns SQL dialect, by default it's SQLPostgresDialect
+func (o *SQLOutputOptions) dialect() string {
+	if o.Dialect == "" {
+		return SQLPostgresDialect
+	}
+	return o.Dialect
+}
+
+// Validate checks whether the SQL dialect is supported
+func (o *SQLOutputOptions) Validate() error {
+	switch o.dialect() {
+	case SQLPostgresDialect, SQLMySQLDialect, SQLSqliteDialect:
+		return nil
+	}
+	return fmt.Errorf("unknown sql dialect: %s, please choose postgres/mysql/sqlite", o.Dialect)
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *SQLFormatter) defaultTemplateContent() string {
+	return ""
+}
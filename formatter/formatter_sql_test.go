@@ -0,0 +1,242 @@
+package formatter
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSQLFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Args:    "nmap -sV 10.0.0.0/24",
+		Start:   1700000000,
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "o'brien.local", Type: "PTR"}}},
+				Status:      HostStatus{State: "up", Reason: "syn-ack"},
+				Trace:       Trace{Port: 80, Protocol: "tcp", Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.45}}},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.X", Accuracy: "95", Line: "100"}}},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "nginx", CPE: []string{"cpe:/a:nginx:nginx"}},
+						Script:   []Script{{ID: "http-title", Output: `It's C:\web`}},
+					},
+					{Protocol: "tcp", PortID: 22, State: PortState{State: "open", Reason: "syn-ack"}, Service: PortService{Name: "ssh"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up", Reason: "echo-reply"},
+				Port:        []Port{{Protocol: "tcp", PortID: 443, State: PortState{State: "open"}, Service: PortService{Name: "https"}}},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		dialect      string
+		err          error
+		wantErr      bool
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "PostgreSQL",
+			wantContains: []string{
+				"-- nmap-formatter 3.0.0 SQL dump (postgres dialect)\n",
+				"\tid bigserial primary key,\n",
+				"\trun_stats_finished_elapsed double precision,\n",
+				"\tscan_id bigint not null,\n",
+				"CREATE INDEX IF NOT EXISTS idx_ports_state ON ports (state_state);\n",
+				"\nBEGIN;\nDELETE FROM nf_schema;\nINSERT INTO nf_schema (version) VALUES ('3.0.0');\n",
+				"INSERT INTO host_names (host_id, name, type) VALUES (currval(pg_get_serial_sequence('hosts', 'id')), 'o''brien.local', 'PTR');\n",
+				"INSERT INTO host_traces_hops (host_id, ttl, ip_address, rtt, host) VALUES (currval(pg_get_serial_sequence('hosts', 'id')), 1, '10.0.0.1', 0.45, '');\n",
+				`INSERT INTO ports_scripts (ports_id, script_id, script_output) VALUES (currval(pg_get_serial_sequence('ports', 'id')), 'http-title', 'It''s C:\web');` + "\n",
+				"COMMIT;\n",
+			},
+		},
+		{
+			name:    "MySQL",
+			dialect: SQLMySQLDialect,
+			wantContains: []string{
+				"\tid bigint not null auto_increment primary key,\n",
+				"\tstate_state varchar(255),\n",
+				"\tstate_reason text,\n",
+				"\tINDEX idx_ports_state (state_state),\n",
+				"SET SESSION sql_mode = CONCAT_WS(',', NULLIF(@@SESSION.sql_mode, ''), 'NO_BACKSLASH_ESCAPES');\nSTART TRANSACTION;\n",
+				"SET @nf_hosts_id = LAST_INSERT_ID();\nINSERT INTO host_traces_hops (host_id, ttl, ip_address, rtt, host) VALUES (@nf_hosts_id, 1, '10.0.0.1', 0.45, '');\n",
+				"SET @nf_ports_id = LAST_INSERT_ID();\n",
+				`INSERT INTO ports_scripts (ports_id, script_id, script_output) VALUES (@nf_ports_id, 'http-title', 'It''s C:\web');` + "\n",
+				"COMMIT;\nSET SESSION sql_mode = @nf_sql_mode;\n",
+			},
+			wantMissing: []string{"CREATE INDEX", `C:\\web`},
+		},
+		{
+			name:    "SQLite",
+			dialect: SQLSqliteDialect,
+			wantContains: []string{
+				"\tid integer not null primary key,\n",
+				"\tuptime_last_boot string,\n",
+				"CREATE INDEX IF NOT EXISTS idx_hosts_scan_id ON hosts (scan_id);\n",
+				"\nBEGIN TRANSACTION;\n",
+				"INSERT OR REPLACE INTO temp.nf_ids (name, id) VALUES ('scans', last_insert_rowid());\n",
+			},
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &SQLFormatter{config: &Config{Writer: writer, CurrentVersion: "3.0.0"}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					SQLOptions: SQLOutputOptions{Dialect: tt.dialect},
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("SQLFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("SQLFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("SQLFormatter.Format() output should not contain %q", missing)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLFormatter_Format_LoadSqlite(t *testing.T) {
+	run := NMAPRun{
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, Script: []Script{{ID: "http-title", Output: `It's C:\web`}}},
+					{Protocol: "tcp", PortID: 22},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.2", AddressType: "ipv4"}},
+				Port:        []Port{{Protocol: "tcp", PortID: 443}},
+			},
+		},
+	}
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("could not open sqlite database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	db.SetMaxOpenConns(1)
+
+	// The same script is loaded twice to make sure rows of every scan reference their own parent rows
+	for _, scanID := range []string{"first", "second"} {
+		writer := &jsonLinesMockedWriter{}
+		f := &SQLFormatter{config: &Config{Writer: writer, CurrentVersion: "3.0.0"}}
+		td := &TemplateData{
+			NMAPRun: run,
+			OutputOptions: OutputOptions{
+				SQLOptions:          SQLOutputOptions{Dialect: SQLSqliteDialect},
+				SqliteOutputOptions: SqliteOutputOptions{ScanIdentifier: scanID},
+			},
+		}
+		if err := f.Format(td, ""); err != nil {
+			t.Fatalf("SQLFormatter.Format() error = %v", err)
+		}
+		if _, err := db.Exec(string(writer.data)); err != nil {
+			t.Fatalf("could not load sql dump: %v", err)
+		}
+	}
+
+	var count int
+	err = db.QueryRow(`
+		SELECT count(*) FROM ports_scripts s
+		JOIN ports p ON p.id = s.ports_id
+		JOIN hosts h ON h.id = p.host_id
+		JOIN scans sc ON sc.id = h.scan_id
+		WHERE sc.nf_identifier = 'second' AND p.port_id = 80 AND s.script_output = ?`, `It's C:\web`).Scan(&count)
+	if err != nil {
+		t.Fatalf("could not query scripts: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("scripts of the second scan = %d, want 1", count)
+	}
+	err = db.QueryRow(`SELECT count(*) FROM hosts h JOIN scans sc ON sc.id = h.scan_id WHERE sc.nf_identifier = 'first'`).Scan(&count)
+	if err != nil {
+		t.Fatalf("could not query hosts: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("hosts of the first scan = %d, want 2", count)
+	}
+	var version string
+	err = db.QueryRow(`SELECT version FROM nf_schema`).Scan(&version)
+	if err != nil || version != "3.0.0" {
+		t.Errorf("nf_schema version = %s, want 3.0.0, error = %v", version, err)
+	}
+}
+
+func TestParseSqliteDDL(t *testing.T) {
+	tables, err := parseSqliteDDL(SqliteDDL)
+	if err != nil {
+		t.Fatalf("parseSqliteDDL() error = %v", err)
+	}
+	if len(tables) != 11 {
+		t.Errorf("parseSqliteDDL() tables = %d, want 11", len(tables))
+	}
+	ports := findSQLTable(tables, "ports")
+	if ports == nil {
+		t.Fatalf("parseSqliteDDL() ports table is missing")
+	}
+	if len(ports.indexes) != 3 {
+		t.Errorf("parseSqliteDDL() ports indexes = %d, want 3", len(ports.indexes))
+	}
+	if !ports.columns[0].primaryKey || !ports.columns[1].notNull || !ports.columns[3].indexed {
+		t.Errorf("parseSqliteDDL() ports columns = %+v", ports.columns)
+	}
+
+	_, err = parseSqliteDDL("CREATE TABLE IF NOT EXISTS scans (\n\tid integer default 1\n);")
+	if err == nil {
+		t.Errorf("parseSqliteDDL() expected error on unknown column definition")
+	}
+	_, err = parseSqliteDDL("CREATE INDEX IF NOT EXISTS idx_hosts_scan_id ON hosts(scan_id);")
+	if err == nil {
+		t.Errorf("parseSqliteDDL() expected error on index of unknown table")
+	}
+}
+
+func TestSQLOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options SQLOutputOptions
+		wantErr bool
+	}{
+		{name: "Defaults", options: SQLOutputOptions{}, wantErr: false},
+		{name: "MySQL", options: SQLOutputOptions{Dialect: "mysql"}, wantErr: false},
+		{name: "SQLite", options: SQLOutputOptions{Dialect: "sqlite"}, wantErr: false},
+		{name: "Unknown dialect", options: SQLOutputOptions{Dialect: "oracle"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("SQLOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// STIXSpecVersion is a version of STIX specification that output conforms to
+	STIXSpecVersion = "2.1"
+	// STIXTimestampFormat is a format of STIX timestamps (UTC with milliseconds)
+	STIXTimestampFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// stixNamespace is a namespace for deterministic STIX Cyber-observable Object identifiers
+// (UUIDv5), defined by STIX 2.1 specification
+var stixNamespace = uuid.MustParse("00abedb4-aa42-466c-9c01-fed23315a9b7")
+
+// STIXFormatter is struct defined for STIX 2.1 bundle Output use-case. Identifiers are
+// deterministic, so exporting the same scan again does not create duplicates
+type STIXFormatter struct {
+	config *Config
+}
+
+// STIXBundle is a root STIX document that contains all objects
+type STIXBundle struct {
+	Type    string       `json:"type"`
+	ID      string       `json:"id"`
+	Objects []STIXObject `json:"objects"`
+}
+
+// STIXObject is a STIX Cyber-observable Object (ipv4-addr, ipv6-addr, mac-addr, domain-name,
+// network-traffic, software) or a relationship between them. Only properties that
+// are related to the object type are filled
+type STIXObject struct {
+	Type             string   `json:"type"`
+	SpecVersion      string   `json:"spec_version"`
+	ID               string   `json:"id"`
+	Created          string   `json:"created,omitempty"`
+	Modified         string   `json:"modified,omitempty"`
+	Value            string   `json:"value,omitempty"`
+	ResolvesToRefs   []string `json:"resolves_to_refs,omitempty"`
+	DstRef           string   `json:"dst_ref,omitempty"`
+	DstPort          int      `json:"dst_port,omitempty"`
+	Protocols        []string `json:"protocols,omitempty"`
+	Name             string   `json:"name,omitempty"`
+	CPE              string   `json:"cpe,omitempty"`
+	Version          string   `json:"version,omitempty"`
+	RelationshipType string   `json:"relationship_type,omitempty"`
+	SourceRef        string   `json:"source_ref,omitempty"`
+	TargetRef        string   `json:"target_ref,omitempty"`
+}
+
+// stixBundleBuilder collects objects, objects with the same ID are merged
+type stixBundleBuilder struct {
+	objects []STIXObject
+	index   map[string]int
+	// timestamp is used in created/modified properties of relationships
+	timestamp string
+	// scan is a part of relationship IDs, relationships of different scans have different
+	// created/modified properties, so they are different objects
+	scan string
+}
+
+// Format the data to STIX 2.1 bundle and output it to appropriate io.Writer
+func (f *STIXFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	encoder := json.NewEncoder(f.config.Writer)
+	if td.OutputOptions.JSONOptions.PrettyPrint {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(stixBundle(&td.NMAPRun))
+}
+
+// stixBundle converts hosts to address & domain name observables, open ports to network traffic
+// and detected services to software objects
+func stixBundle(n *NMAPRun) *STIXBundle {
+	b := &stixBundleBuilder{
+		index:     map[string]int{},
+		timestamp: time.Unix(int64(n.Start), 0).UTC().Format(STIXTimestampFormat),
+		scan:      fmt.Sprintf("%s|%d", n.Args, n.Start),
+	}
+	for i := range n.Host {
+		b.addHost(&n.Host[i])
+	}
+	return &STIXBundle{
+		Type:    "bundle",
+		ID:      "bundle--" + uuid.NewSHA1(stixNamespace, []byte(fmt.Sprintf("%s|%d|%s", n.Args, n.Start, strings.Join(b.ids(), ",")))).String(),
+		Objects: b.objects,
+	}
+}
+
+// addHost adds all objects related to the host
+func (b *stixBundleBuilder) addHost(h *Host) {
+	var macRefs []string
+	for _, a := range h.HostAddress {
+		if a.AddressType == "mac" {
+			macRefs = append(macRefs, b.add(STIXObject{Type: "mac-addr", Value: strings.ToLower(a.Address)}, "value"))
+		}
+	}
+
+	var ipRefs []string
+	var ipTypes []string
+	for _, a := range h.HostAddress {
+		if a.AddressType != "ipv4" && a.AddressType != "ipv6" {
+			continue
+		}
+		ipRefs = append(ipRefs, b.add(STIXObject{Type: a.AddressType + "-addr", Value: a.Address, ResolvesToRefs: macRefs}, "value"))
+		ipTypes = append(ipTypes, a.AddressType)
+	}
+	if len(ipRefs) == 0 {
+		return
+	}
+
+	for _, hostname := range h.HostNames.HostName {
+		b.add(STIXObject{Type: "domain-name", Value: hostname.Name, ResolvesToRefs: ipRefs}, "value")
+	}
+
+	for i := range h.Port {
+		port := &h.Port[i]
+		if port.State.State != "open" {
+			continue
+		}
+		trafficRef := b.add(STIXObject{
+			Type:      "network-traffic",
+			DstRef:    ipRefs[0],
+			DstPort:   port.PortID,
+			Protocols: []string{ipTypes[0], strings.ToLower(port.Protocol)},
+		}, "dst_ref", "dst_port", "protocols")
+
+		if port.Service.Product == "" && len(port.Service.CPE) == 0 {
+			continue
+		}
+		software := STIXObject{Type: "software", Name: port.Service.Product, Version: port.Service.Version}
+		if software.Name == "" {
+			software.Name = port.Service.Name
+		}
+		if len(port.Service.CPE) > 0 {
+			software.CPE = port.Service.CPE[0]
+		}
+		softwareRef := b.add(software, "name", "cpe", "version")
+		b.relationship(softwareRef, "related-to", trafficRef)
+	}
+}
+
+// add appends the object with deterministic ID generated from ID contributing properties,
+// if the object already exists, only resolves_to_refs are merged. Returns ID of the object
+func (b *stixBundleBuilder) add(o STIXObject, properties ...string) string {
+	o.SpecVersion = STIXSpecVersion
+	o.ID = o.Type + "--" + stixDeterministicID(o, properties...)
+	if i, ok := b.index[o.ID]; ok {
+		for _, ref := range o.ResolvesToRefs {
+			if !slices.Contains(b.objects[i].ResolvesToRefs, ref) {
+				b.objects[i].ResolvesToRefs = append(b.objects[i].ResolvesToRefs, ref)
+			}
+		}
+		return o.ID
+	}
+	if o.ResolvesToRefs != nil {
+		o.ResolvesToRefs = append([]string{}, o.ResolvesToRefs...)
+	}
+	b.index[o.ID] = len(b.objects)
+	b.objects = append(b.objects, o)
+	return o.ID
+}
+
+// relationship adds relationship object between two objects, ID is deterministic within the scan
+func (b *stixBundleBuilder) relationship(source string, kind string, target string) {
+	id := "relationship--" + uuid.NewSHA1(stixNamespace, []byte(b.scan+"|"+source+"|"+kind+"|"+target)).String()
+	if _, ok := b.index[id]; ok {
+		return
+	}
+	b.index[id] = len(b.objects)
+	b.objects = append(b.objects, STIXObject{
+		Type:             "relationship",
+		SpecVersion:      STIXSpecVersion,
+		ID:               id,
+		Created:          b.timestamp,
+		Modified:         b.timestamp,
+		RelationshipType: kind,
+		SourceRef:        source,
+		TargetRef:        target,
+	})
+}
+
+// ids returns identifiers of all objects in the bundle
+func (b *stixBundleBuilder) ids() []string {
+	ids := make([]string, len(b.objects))
+	for i := range b.objects {
+		ids[i] = b.objects[i].ID
+	}
+	return ids
+}
+
+// stixDeterministicID returns UUIDv5 generated from canonical JSON of ID contributing properties
+func stixDeterministicID(o STIXObject, properties ...string) string {
+	encoded, _ := json.Marshal(o)
+	all := map[string]interface{}{}
+	_ = json.Unmarshal(encoded, &all)
+
+	contributing := map[string]interface{}{}
+	for _, p := range properties {
+		if v, ok := all[p]; ok {
+			contributing[p] = v
+		}
+	}
+	// Map keys are sorted by encoding/json, HTML escaping is disabled to keep JSON canonical
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(contributing)
+	return uuid.NewSHA1(stixNamespace, bytes.TrimSpace(buf.Bytes())).String()
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *STIXFormatter) defaultTemplateContent() string {
+	return ""
+}
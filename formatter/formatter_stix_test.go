@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSTIXFormatter_Format(t *testing.T) {
+	nginx := PortService{Name: "http", Product: "nginx", Version: "1.18.0", CPE: []string{"cpe:/a:igor_sysoev:nginx:1.18.0"}}
+	run := NMAPRun{
+		Args:  "nmap -sV 192.168.1.0/24",
+		Start: 1700000000,
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{
+					{Address: "192.168.1.10", AddressType: "ipv4"},
+					{Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"},
+				},
+				HostNames: HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: nginx},
+					{Protocol: "tcp", PortID: 443, State: PortState{State: "closed"}, Service: nginx},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "2001:db8::1", AddressType: "ipv6"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Port: []Port{
+					{Protocol: "tcp", PortID: 8080, State: PortState{State: "open"}, Service: nginx},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "open"}, Service: PortService{Name: "domain"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		err     error
+		wantErr bool
+	}{
+		{name: "Bundle"},
+		{name: "Error", err: errors.New("some error happened"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &STIXFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{NMAPRun: run}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("STIXFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err != nil {
+				return
+			}
+			var bundle STIXBundle
+			if err := json.Unmarshal(writer.data, &bundle); err != nil {
+				t.Fatalf("could not unmarshal STIX output: %v", err)
+			}
+
+			var types []string
+			byType := map[string][]STIXObject{}
+			for _, o := range bundle.Objects {
+				types = append(types, o.Type)
+				byType[o.Type] = append(byType[o.Type], o)
+			}
+			wantTypes := []string{
+				"mac-addr", "ipv4-addr", "domain-name", "network-traffic", "software", "relationship",
+				"ipv6-addr", "network-traffic", "relationship", "network-traffic",
+			}
+			if !reflect.DeepEqual(types, wantTypes) {
+				t.Errorf("STIXFormatter.Format() object types = %v, want %v", types, wantTypes)
+			}
+
+			// The same value as generated by STIX 2.1 reference implementation (UUIDv5 of `{"value":"192.168.1.10"}`)
+			ipv4 := byType["ipv4-addr"][0]
+			if ipv4.ID != "ipv4-addr--ae627da4-8e1f-5464-b490-f81156e8ead5" {
+				t.Errorf("STIXFormatter.Format() ipv4-addr id = %s", ipv4.ID)
+			}
+			if !reflect.DeepEqual(ipv4.ResolvesToRefs, []string{byType["mac-addr"][0].ID}) {
+				t.Errorf("STIXFormatter.Format() ipv4-addr resolves_to_refs = %v", ipv4.ResolvesToRefs)
+			}
+			domain := byType["domain-name"][0]
+			if !reflect.DeepEqual(domain.ResolvesToRefs, []string{ipv4.ID, byType["ipv6-addr"][0].ID}) {
+				t.Errorf("STIXFormatter.Format() domain-name resolves_to_refs = %v", domain.ResolvesToRefs)
+			}
+			traffic := byType["network-traffic"][0]
+			if traffic.DstRef != ipv4.ID || traffic.DstPort != 80 || !reflect.DeepEqual(traffic.Protocols, []string{"ipv4", "tcp"}) {
+				t.Errorf("STIXFormatter.Format() wrong network-traffic: %+v", traffic)
+			}
+			software := byType["software"][0]
+			if software.Name != "nginx" || software.Version != "1.18.0" || software.CPE != nginx.CPE[0] {
+				t.Errorf("STIXFormatter.Format() wrong software: %+v", software)
+			}
+			relationship := byType["relationship"][1]
+			if relationship.SourceRef != software.ID || relationship.TargetRef != byType["network-traffic"][1].ID ||
+				relationship.Created != "2023-11-14T22:13:20.000Z" {
+				t.Errorf("STIXFormatter.Format() wrong relationship: %+v", relationship)
+			}
+
+			again := &jsonLinesMockedWriter{}
+			f.config.Writer = again
+			if err := f.Format(td, ""); err != nil || string(again.data) != string(writer.data) {
+				t.Errorf("STIXFormatter.Format() output is not deterministic, error = %v", err)
+			}
+
+			// Relationship of another scan is a different object, since it has different timestamps
+			next := &jsonLinesMockedWriter{}
+			f.config.Writer = next
+			nextRun := td.NMAPRun
+			nextRun.Start += 3600
+			if err := f.Format(&TemplateData{NMAPRun: nextRun}, ""); err != nil {
+				t.Fatalf("STIXFormatter.Format() error = %v", err)
+			}
+			var nextBundle STIXBundle
+			if err := json.Unmarshal(next.data, &nextBundle); err != nil {
+				t.Fatalf("could not unmarshal STIX output: %v", err)
+			}
+			for _, o := range nextBundle.Objects {
+				if o.ID == relationship.ID {
+					t.Errorf("STIXFormatter.Format() relationship of another scan has the same id: %s", o.ID)
+				}
+				if o.Type == "software" && o.ID != software.ID {
+					t.Errorf("STIXFormatter.Format() software of another scan has different id: %s", o.ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package formatter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	// TableColorAuto enables colors only when the output is a terminal (default one)
+	TableColorAuto = "auto"
+	// TableColorAlways enables colors even if the output is not a terminal
+	TableColorAlways = "always"
+	// TableColorNever disables colors
+	TableColorNever = "never"
+
+	// TableOverflowTruncate truncates long values to fit the terminal width (default one)
+	TableOverflowTruncate = "truncate"
+	// TableOverflowWrap wraps long values to the next line
+	TableOverflowWrap = "wrap"
+
+	// TableDefaultWidth is a width used when the output is not a terminal
+	TableDefaultWidth = 120
+	// tableMinLastColumnWidth is the narrowest width of the last column, wider tables are not fitted
+	tableMinLastColumnWidth = 10
+	// tableColumnGap is a space between columns
+	tableColumnGap = "  "
+)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiGray  = "\x1b[90m"
+)
+
+// TableFormatter is struct defined for colored terminal table Output use-case
+type TableFormatter struct {
+	config *Config
+}
+
+// tableWriter writes aligned lines that fit into the width, colors are optional,
+// port state colors are the same as in the Dot (Graphviz) output
+type tableWriter struct {
+	b      strings.Builder
+	color  bool
+	width  int
+	wrap   bool
+	colors *DotOutputOptions
+}
+
+// Format the data to host/port tables and output it to appropriate io.Writer
+func (f *TableFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	options := &td.OutputOptions.TableOptions
+	isTerminal, terminalWidth := terminalInfo(f.config.Writer)
+	t := &tableWriter{
+		color:  options.colors(isTerminal),
+		width:  options.width(terminalWidth),
+		wrap:   options.Overflow == TableOverflowWrap,
+		colors: &td.OutputOptions.DotOptions,
+	}
+	n := &td.NMAPRun
+	t.line(t.colorize(fmt.Sprintf("NMAP Scan Result: %s", n.StartStr), ansiBold))
+	t.line(t.colorize(fmt.Sprintf(
+		"%s %s, hosts up: %d, down: %d, total: %d",
+		n.Scanner, n.Version, n.RunStats.Hosts.Up, n.RunStats.Hosts.Down, n.RunStats.Hosts.Total,
+	), ansiGray))
+	for i := range n.Host {
+		t.b.WriteString("\n")
+		t.host(&n.Host[i])
+	}
+	_, err = f.config.Writer.Write([]byte(t.b.String()))
+	return err
+}
+
+// terminalInfo checks whether the writer is a terminal and returns its width
+func terminalInfo(w io.Writer) (bool, int) {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return false, 0
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return true, 0
+	}
+	return true, width
+}
+
+// host writes the host title and table of ports with script output below every port
+func (t *tableWriter) host(h *Host) {
+	title := h.JoinedAddresses("/")
+	for _, hostname := range h.HostNames.HostName {
+		title += " / " + hostname.Name
+	}
+	state := h.Status.State
+	if state == "up" {
+		state = t.colorize(state, tableHexColor(dotOption(t.colors.OpenPortColor, DotOpenPortColor)))
+	} else {
+		state = t.colorize(state, tableHexColor(dotOption(t.colors.ClosedPortColor, DotClosedPortColor)))
+	}
+	t.line(t.colorize(title, ansiBold) + " (" + state + ")")
+	if len(h.OS.OSMatch) > 0 {
+		t.fitted(fmt.Sprintf("OS: %s (%s%%)", h.OS.OSMatch[0].Name, h.OS.OSMatch[0].Accuracy), "", ansiGray)
+	}
+	if len(h.Port) == 0 {
+		t.line(t.colorize("No ports", ansiGray))
+		return
+	}
+
+	rows := [][]string{{"PORT", "STATE", "SERVICE", "VERSION"}}
+	for i := range h.Port {
+		p := &h.Port[i]
+		version := strings.TrimSpace(strings.Join([]string{p.Service.Product, p.Service.Version, p.Service.ExtraInfo}, " "))
+		rows = append(rows, []string{fmt.Sprintf("%d/%s", p.PortID, p.Protocol), p.State.State, p.Service.Name, version})
+	}
+	widths := make([]int, len(rows[0])-1)
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+	for i, row := range rows {
+		prefix := strings.Builder{}
+		indent := 0
+		for j, width := range widths {
+			cell := row[j] + strings.Repeat(" ", width-utf8.RuneCountInString(row[j])) + tableColumnGap
+			indent += utf8.RuneCountInString(cell)
+			switch {
+			case i == 0:
+				cell = t.colorize(cell, ansiBold)
+			case j == 1:
+				cell = t.colorize(cell, tableHexColor(t.colors.portStateColor(&h.Port[i-1])))
+			}
+			prefix.WriteString(cell)
+		}
+		lastColor := ""
+		if i == 0 {
+			lastColor = ansiBold
+		}
+		lines := t.fit(row[len(row)-1], t.width-indent)
+		for k, line := range lines {
+			if k == 0 {
+				t.line(strings.TrimRight(prefix.String()+t.colorize(line, lastColor), " "))
+			} else {
+				t.line(strings.Repeat(" ", indent) + t.colorize(line, lastColor))
+			}
+		}
+		if i > 0 {
+			t.scripts(h.Port[i-1].Script)
+		}
+	}
+}
+
+// scripts writes script output the same way as nmap does: every line is prefixed with `|`
+// and the last line of the last script is prefixed with `|_`
+func (t *tableWriter) scripts(scripts []Script) {
+	var lines []string
+	for _, s := range scripts {
+		output := strings.Split(strings.Trim(strings.ReplaceAll(s.Output, "\r\n", "\n"), "\n"), "\n")
+		if len(output) == 1 {
+			lines = append(lines, s.ID+": "+output[0])
+			continue
+		}
+		lines = append(lines, s.ID+":")
+		lines = append(lines, output...)
+	}
+	for i, line := range lines {
+		prefix := "| "
+		if i == len(lines)-1 {
+			prefix = "|_"
+		}
+		t.fitted(line, prefix, ansiGray)
+	}
+}
+
+// fitted writes the text that is fitted into the width, continuation lines keep the prefix
+func (t *tableWriter) fitted(text string, prefix string, color string) {
+	continuation := prefix
+	if prefix == "|_" {
+		continuation = "| "
+	}
+	for i, line := range t.fit(text, t.width-utf8.RuneCountInString(prefix)) {
+		if i == 0 {
+			t.line(t.colorize(prefix+line, color))
+		} else {
+			t.line(t.colorize(continuation+line, color))
+		}
+	}
+}
+
+// fit truncates or wraps the text to the width, too narrow width is extended to tableMinLastColumnWidth
+func (t *tableWriter) fit(text string, width int) []string {
+	width = max(width, tableMinLastColumnWidth)
+	text = strings.ReplaceAll(text, "\t", "    ")
+	runes := []rune(text)
+	if len(runes) <= width {
+		return []string{text}
+	}
+	if !t.wrap {
+		return []string{string(runes[:width-1]) + "…"}
+	}
+	var lines []string
+	for len(runes) > width {
+		// Break on the last space if there is one, otherwise break in the middle of the word
+		end := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				end = i
+				break
+			}
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:end]), " "))
+		runes = []rune(strings.TrimLeft(string(runes[end:]), " "))
+	}
+	if len(runes) > 0 {
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// line writes the line to the output
+func (t *tableWriter) line(s string) {
+	t.b.WriteString(s + "\n")
+}
+
+// colorize wraps the text with ANSI color, if colors are enabled
+func (t *tableWriter) colorize(s string, color string) string {
+	if !t.color || color == "" || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// tableHexColor converts `#RRGGBB` color to ANSI true color sequence, named colors are displayed as gray
+func tableHexColor(hex string) string {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if len(hex) != 7 || err != nil {
+		return ansiGray
+	}
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", value>>16&0xFF, value>>8&0xFF, value&0xFF)
+}
+
+// colors returns whether colors are enabled, NO_COLOR environment variable disables colors in auto mode
+func (o *TableOutputOptions) colors(isTerminal bool) bool {
+	switch o.Color {
+	case TableColorAlways:
+		return true
+	case TableColorNever:
+		return false
+	}
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return isTerminal && !noColor
+}
+
+// width returns the table width: the one that is set by the user, terminal width
+// or TableDefaultWidth if the output is not a terminal
+func (o *TableOutputOptions) width(terminalWidth int) int {
+	if o.Width > 0 {
+		return o.Width
+	}
+	if terminalWidth > 0 {
+		return terminalWidth
+	}
+	return TableDefaultWidth
+}
+
+// Validate checks whether color mode, overflow mode and width are correct
+func (o *TableOutputOptions) Validate() error {
+	switch o.Color {
+	case "", TableColorAuto, TableColorAlways, TableColorNever:
+	default:
+		return fmt.Errorf("unknown table color mode: %s, please choose auto/always/never", o.Color)
+	}
+	switch o.Overflow {
+	case "", TableOverflowTruncate, TableOverflowWrap:
+	default:
+		return fmt.Errorf("unknown table overflow mode: %s, please choose truncate/wrap", o.Overflow)
+	}
+	if o.Width < 0 {
+		return fmt.Errorf("table width should not be negative: %d", o.Width)
+	}
+	return nil
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *TableFormatter) defaultTemplateContent() string {
+	return ""
+}
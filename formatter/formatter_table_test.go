@@ -0,0 +1,205 @@
+package formatter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTableFormatter_Format(t *testing.T) {
+	// Long values are truncated or wrapped, scripts are written under the port
+	run := NMAPRun{
+		Scanner:  "nmap",
+		Args:     "nmap -sV -O --traceroute 10.0.0.0/23",
+		Start:    1700000000,
+		StartStr: "Tue Nov 14 22:13:20 2023",
+		Version:  "7.94",
+		RunStats: RunStats{
+			Finished: Finished{Time: 1700000012, Elapsed: 12.35},
+			Hosts:    StatHosts{Up: 2, Down: 1, Total: 3},
+		},
+		Host: []Host{
+			{
+				StartTime:   1700000001,
+				EndTime:     1700000011,
+				HostAddress: []HostAddress{{Address: "10.0.0.1", AddressType: "ipv4"}, {Address: "00:1A:2B:3C:4D:5E", AddressType: "mac"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local", Type: "PTR"}}},
+				Status:      HostStatus{State: "up", Reason: "syn-ack"},
+				Distance:    Distance{Value: 2},
+				OS: OS{
+					OSMatch: []OSMatch{{Name: "Linux 5.X", Accuracy: "95", Line: "100"}},
+					OSClass: []OSClass{{OSFamily: "Linux"}},
+				},
+				Trace: Trace{
+					Port:     80,
+					Protocol: "tcp",
+					Hops:     []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.5}, {TTL: 2, IPAddr: "10.0.0.1", RTT: 1.25}},
+				},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open", Reason: "syn-ack"},
+						Service:  PortService{Name: "http", Product: "nginx", Version: "1.18.0", ExtraInfo: "Ubuntu Linux with a very long extra information"},
+						Script:   []Script{{ID: "http-title", Output: "Welcome page"}, {ID: "http-headers", Output: "\n  Server: nginx\n  Connection: close\n"}},
+					},
+					{Protocol: "udp", PortID: 53, State: PortState{State: "closed", Reason: "port-unreach"}, Service: PortService{Name: "domain"}},
+				},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.1.2", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up", Reason: "echo-reply"},
+				OS:          OS{OSClass: []OSClass{{OSFamily: "Windows"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.254", RTT: 0.75}, {TTL: 2, IPAddr: "10.0.1.2", RTT: 1.5}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 445, State: PortState{State: "filtered", Reason: "no-response"}, Service: PortService{Name: "microsoft-ds"}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "10.0.0.3", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "down", Reason: "no-response"},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		options      TableOutputOptions
+		colors       DotOutputOptions
+		err          error
+		wantErr      bool
+		wantContains []string
+		wantMissing  []string
+	}{
+		{
+			name: "Default options",
+			wantContains: []string{
+				"NMAP Scan Result: Tue Nov 14 22:13:20 2023\nnmap 7.94, hosts up: 2, down: 1, total: 3\n\n",
+				"10.0.0.1 / 00:1A:2B:3C:4D:5E / web.local (up)\nOS: Linux 5.X (95%)\n",
+				"PORT    STATE   SERVICE  VERSION\n",
+				"80/tcp  open    http     nginx 1.18.0 Ubuntu Linux with a very long extra information\n",
+				"| http-title: Welcome page\n| http-headers:\n|   Server: nginx\n|_  Connection: close\n",
+				"53/udp  closed  domain\n",
+				"445/tcp  filtered  microsoft-ds\n",
+				"10.0.0.3 (down)\nNo ports\n",
+			},
+			wantMissing: []string{"\x1b["},
+		},
+		{
+			name:    "Truncated to the width",
+			options: TableOutputOptions{Width: 50},
+			wantContains: []string{
+				"80/tcp  open    http     nginx 1.18.0 Ubuntu Linu…\n",
+				"|   Server: nginx\n",
+			},
+		},
+		{
+			name:    "Wrapped to the width",
+			options: TableOutputOptions{Width: 50, Overflow: TableOverflowWrap},
+			wantContains: []string{
+				"80/tcp  open    http     nginx 1.18.0 Ubuntu Linux\n                         with a very long extra\n",
+			},
+		},
+		{
+			name:    "Colors",
+			options: TableOutputOptions{Color: TableColorAlways},
+			wantContains: []string{
+				"\x1b[1m10.0.0.1 / 00:1A:2B:3C:4D:5E / web.local\x1b[0m (\x1b[38;2;34;139;34mup\x1b[0m)\n",
+				"\x1b[38;2;34;139;34mopen    \x1b[0m",
+				"\x1b[38;2;255;174;0mfiltered  \x1b[0m",
+				"\x1b[90m|_  Connection: close\x1b[0m\n",
+				"(\x1b[38;2;220;20;60mdown\x1b[0m)",
+			},
+		},
+		{
+			name:    "Colors from the Dot options",
+			options: TableOutputOptions{Color: TableColorAlways},
+			colors:  DotOutputOptions{OpenPortColor: "#0000FF", ClosedPortColor: "red"},
+			wantContains: []string{
+				"(\x1b[38;2;0;0;255mup\x1b[0m)\n",
+				"\x1b[38;2;0;0;255mopen    \x1b[0m",
+				"(\x1b[90mdown\x1b[0m)",
+			},
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &TableFormatter{config: &Config{Writer: writer}}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					TableOptions: tt.options,
+					DotOptions:   tt.colors,
+				},
+			}
+			if err := f.Format(td, f.defaultTemplateContent()); (err != nil) != tt.wantErr {
+				t.Errorf("TableFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			output := string(writer.data)
+			for _, want := range tt.wantContains {
+				if !strings.Contains(output, want) {
+					t.Errorf("TableFormatter.Format() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(output, missing) {
+					t.Errorf("TableFormatter.Format() output should not contain %q", missing)
+				}
+			}
+		})
+	}
+}
+
+func TestTableOutputOptions_colors(t *testing.T) {
+	tests := []struct {
+		name       string
+		color      string
+		noColor    bool
+		isTerminal bool
+		want       bool
+	}{
+		{name: "Auto for terminal", isTerminal: true, want: true},
+		{name: "Auto for file", isTerminal: false, want: false},
+		{name: "Auto with NO_COLOR", isTerminal: true, noColor: true, want: false},
+		{name: "Always", color: TableColorAlways, want: true},
+		{name: "Never", color: TableColorNever, isTerminal: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.noColor {
+				t.Setenv("NO_COLOR", "1")
+			}
+			o := &TableOutputOptions{Color: tt.color}
+			if got := o.colors(tt.isTerminal); got != tt.want {
+				t.Errorf("TableOutputOptions.colors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableOutputOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options TableOutputOptions
+		wantErr bool
+	}{
+		{name: "Default", options: TableOutputOptions{}},
+		{name: "Valid options", options: TableOutputOptions{Color: TableColorNever, Overflow: TableOverflowWrap, Width: 80}},
+		{name: "Wrong color mode", options: TableOutputOptions{Color: "rainbow"}, wantErr: true},
+		{name: "Wrong overflow mode", options: TableOutputOptions{Overflow: "hide"}, wantErr: true},
+		{name: "Negative width", options: TableOutputOptions{Width: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.options.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TableOutputOptions.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
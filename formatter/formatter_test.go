@@ -88,6 +88,168 @@ func TestNew(t *testing.T) {
 			},
 			want: &ExcelFormatter{config: &Config{OutputFormat: ExcelOutput}},
 		},
+		{
+			name: "JSON Lines output",
+			args: args{
+				config: &Config{
+					OutputFormat: JSONLinesOutput,
+				},
+			},
+			want: &JSONLinesFormatter{config: &Config{OutputFormat: JSONLinesOutput}},
+		},
+		{
+			name: "YAML output",
+			args: args{
+				config: &Config{
+					OutputFormat: YAMLOutput,
+				},
+			},
+			want: &YAMLFormatter{config: &Config{OutputFormat: YAMLOutput}},
+		},
+		{
+			name: "XML output",
+			args: args{
+				config: &Config{
+					OutputFormat: XMLOutput,
+				},
+			},
+			want: &XMLFormatter{config: &Config{OutputFormat: XMLOutput}},
+		},
+		{
+			name: "PDF output",
+			args: args{
+				config: &Config{
+					OutputFormat: PDFOutput,
+				},
+			},
+			want: &PDFFormatter{config: &Config{OutputFormat: PDFOutput}},
+		},
+		{
+			name: "Mermaid output",
+			args: args{
+				config: &Config{
+					OutputFormat: MermaidOutput,
+				},
+			},
+			want: &MermaidFormatter{config: &Config{OutputFormat: MermaidOutput}},
+		},
+		{
+			name: "GraphML output",
+			args: args{
+				config: &Config{
+					OutputFormat: GraphMLOutput,
+				},
+			},
+			want: &GraphMLFormatter{config: &Config{OutputFormat: GraphMLOutput}},
+		},
+		{
+			name: "GEXF output",
+			args: args{
+				config: &Config{
+					OutputFormat: GEXFOutput,
+				},
+			},
+			want: &GEXFFormatter{config: &Config{OutputFormat: GEXFOutput}},
+		},
+		{
+			name: "CycloneDX output",
+			args: args{
+				config: &Config{
+					OutputFormat: CycloneDXOutput,
+				},
+			},
+			want: &CycloneDXFormatter{config: &Config{OutputFormat: CycloneDXOutput}},
+		},
+		{
+			name: "STIX 2.1 output",
+			args: args{
+				config: &Config{
+					OutputFormat: STIXOutput,
+				},
+			},
+			want: &STIXFormatter{config: &Config{OutputFormat: STIXOutput}},
+		},
+		{
+			name: "Elastic Common Schema (ECS) output",
+			args: args{
+				config: &Config{
+					OutputFormat: ECSOutput,
+				},
+			},
+			want: &ECSFormatter{config: &Config{OutputFormat: ECSOutput}},
+		},
+		{
+			name: "Prometheus output",
+			args: args{
+				config: &Config{
+					OutputFormat: PrometheusOutput,
+				},
+			},
+			want: &PrometheusFormatter{config: &Config{OutputFormat: PrometheusOutput}},
+		},
+		{
+			name: "Ansible inventory output",
+			args: args{
+				config: &Config{
+					OutputFormat: AnsibleOutput,
+				},
+			},
+			want: &AnsibleFormatter{config: &Config{OutputFormat: AnsibleOutput}},
+		},
+		{
+			name: "Apache Parquet output",
+			args: args{
+				config: &Config{
+					OutputFormat: ParquetOutput,
+				},
+			},
+			want: &ParquetFormatter{config: &Config{OutputFormat: ParquetOutput}},
+		},
+		{
+			name: "SQL dump output",
+			args: args{
+				config: &Config{
+					OutputFormat: SQLOutput,
+				},
+			},
+			want: &SQLFormatter{config: &Config{OutputFormat: SQLOutput}},
+		},
+		{
+			name: "Neo4j Cypher output",
+			args: args{
+				config: &Config{
+					OutputFormat: CypherOutput,
+				},
+			},
+			want: &CypherFormatter{config: &Config{OutputFormat: CypherOutput}},
+		},
+		{
+			name: "LaTeX output",
+			args: args{
+				config: &Config{
+					OutputFormat: LaTeXOutput,
+				},
+			},
+			want: &LaTeXFormatter{config: &Config{OutputFormat: LaTeXOutput}},
+		},
+		{
+			name: "AsciiDoc output",
+			args: args{
+				config: &Config{
+					OutputFormat: AsciiDocOutput,
+				},
+			},
+			want: &AsciiDocFormatter{config: &Config{OutputFormat: AsciiDocOutput}},
+		},
+		{
+			name: "Table output",
+			args: args{
+				config: &Config{
+					OutputFormat: TableOutput,
+				},
+			},
+			want: &TableFormatter{config: &Config{OutputFormat: TableOutput}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,38 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// XMLFormatter is struct defined for nmap XML Output use-case, it's mainly used to
+// pass filtered scan results to other tools that read nmap XML only
+type XMLFormatter struct {
+	config *Config
+}
+
+// Format the data back to nmap XML and output it to appropriate io.Writer
+func (f *XMLFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	header := xml.Header + "<!DOCTYPE nmaprun>\n"
+	if td.NMAPRun.XMLStylesheet != "" {
+		header += fmt.Sprintf("<?xml-stylesheet %s?>\n", td.NMAPRun.XMLStylesheet)
+	}
+	_, err = f.config.Writer.Write([]byte(header))
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(f.config.Writer)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(td.NMAPRun)
+	if err != nil {
+		return err
+	}
+	_, err = f.config.Writer.Write([]byte("\n"))
+	return err
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *XMLFormatter) defaultTemplateContent() string {
+	return ""
+}
@@ -0,0 +1,220 @@
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const xmlRoundTripInput = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<?xml-stylesheet href="file:///usr/bin/../share/nmap/nmap.xsl" type="text/xsl"?>
+<!-- Nmap 7.92 scan initiated Sat Jan  1 10:00:00 2022 as: nmap -sV -O -&#45;traceroute -oX - scanme.nmap.org -->
+<nmaprun scanner="nmap" args="nmap -sV -O -&#45;traceroute -oX - scanme.nmap.org" start="1641031200" startstr="Sat Jan  1 10:00:00 2022" version="7.92" xmloutputversion="1.05">
+<scaninfo type="syn" protocol="tcp" numservices="1000" services="1,3-4,6-7"/>
+<verbose level="0"/>
+<debugging level="0"/>
+<host starttime="1641031201" endtime="1641031230"><status state="up" reason="echo-reply" reason_ttl="53"/>
+<address addr="45.33.32.156" addrtype="ipv4"/>
+<address addr="00:11:22:33:44:55" addrtype="mac" vendor="Acme &amp; Co"/>
+<hostnames>
+<hostname name="scanme.nmap.org" type="user"/>
+<hostname name="scanme.nmap.org" type="PTR"/>
+</hostnames>
+<ports><extraports state="closed" count="996">
+<extrareasons reason="resets" count="996"/>
+</extraports>
+<port protocol="tcp" portid="22"><state state="open" reason="syn-ack" reason_ttl="53"/><service name="ssh" product="OpenSSH" version="6.6.1p1 Ubuntu 2ubuntu2.13" extrainfo="Ubuntu Linux; protocol 2.0" ostype="Linux" method="probed" conf="10"><cpe>cpe:/a:openbsd:openssh:6.6.1p1</cpe><cpe>cpe:/o:linux:linux_kernel</cpe></service><script id="ssh-hostkey" output="&#xa;  1024 ac:00:a0:1a:82:ff:cc:55:99:dc:67:2b:34:97:6b:75 (DSA)&#xa;  2048 20:3d:2d:44:62:2a:b0:5a:9d:b5:b3:05:14:c2:a6:b2 (RSA)"/></port>
+<port protocol="tcp" portid="80"><state state="open" reason="syn-ack" reason_ttl="53"/><service name="http" product="Apache httpd" version="2.4.7" extrainfo="(Ubuntu)" method="probed" conf="10"><cpe>cpe:/a:apache:http_server:2.4.7</cpe></service><script id="http-title" output="Go ahead and ScanMe! &lt;&quot;quoted&quot;&gt;"/></port>
+<port protocol="tcp" portid="9929"><state state="filtered" reason="no-response" reason_ttl="0"/><service name="nping-echo" method="table" conf="3"/></port>
+</ports>
+<os><portused state="open" proto="tcp" portid="22"/>
+<portused state="closed" proto="tcp" portid="1"/>
+<osclass type="general purpose" vendor="Linux" osfamily="Linux" osgen="4.X" accuracy="95"><cpe>cpe:/o:linux:linux_kernel:4</cpe></osclass>
+<osmatch name="Linux 4.15 - 5.6" accuracy="95" line="63640"/>
+</os>
+<uptime seconds="2209537" lastboot="Mon Dec  6 20:14:23 2021"/>
+<distance value="11"/>
+<tcpsequence index="262" difficulty="Good luck!" values="F6B1C21B,8CE6E2C,B0EE7B30"/>
+<ipidsequence class="All zeros" values="0,0,0"/>
+<tcptssequence class="1000HZ" values="83B1AAE3,83B1AB47"/>
+<trace port="256" proto="tcp">
+<hop ttl="1" ipaddr="192.168.1.1" rtt="0.52"/>
+<hop ttl="2" ipaddr="10.0.0.1" rtt="--"/>
+<hop ttl="3" ipaddr="45.33.32.156" rtt="181.92" host="scanme.nmap.org"/>
+</trace>
+<times srtt="180386" rttvar="2209" to="200000"/>
+</host>
+<host><status state="down" reason="no-response" reason_ttl="0"/>
+<address addr="10.10.10.10" addrtype="ipv4"/>
+</host>
+<runstats><finished time="1641031230" timestr="Sat Jan  1 10:00:30 2022" summary="Nmap done at Sat Jan  1 10:00:30 2022; 2 IP addresses (1 host up) scanned in 30.12 seconds" elapsed="30.12" exit="success"/><hosts up="1" down="1" total="2"/>
+</runstats>
+</nmaprun>
+`
+
+// parseXML parses nmap XML the same way the main workflow does
+func parseXML(t *testing.T, content string) NMAPRun {
+	w := &MainWorkflow{
+		Config: &Config{
+			InputFileConfig: InputFileConfig{
+				Source: io.NopCloser(strings.NewReader(content)),
+			},
+		},
+	}
+	run, err := w.parse()
+	if err != nil {
+		t.Fatalf("could not parse XML: %v", err)
+	}
+	return run
+}
+
+func TestXMLFormatter_FormatRoundTrip(t *testing.T) {
+	original := parseXML(t, xmlRoundTripInput)
+	if len(original.Host) != 2 || len(original.Host[0].Port) != 3 {
+		t.Fatalf("unexpected parsed data: %+v", original)
+	}
+
+	// parse -> emit -> parse should result in the same data
+	writer := &jsonLinesMockedWriter{}
+	f := &XMLFormatter{config: &Config{Writer: writer}}
+	err := f.Format(&TemplateData{NMAPRun: original}, "")
+	if err != nil {
+		t.Fatalf("XMLFormatter.Format() error = %v", err)
+	}
+	// Down host has neither scan times nor hostnames in the input
+	if !strings.Contains(string(writer.data), "  <host>\n    <status state=\"down\"") {
+		t.Errorf("XMLFormatter.Format() down host should not have absent attributes, output = \n%s", writer.data)
+	}
+	emitted := parseXML(t, string(writer.data))
+	if !reflect.DeepEqual(original, emitted) {
+		t.Errorf("XMLFormatter.Format() round-trip data = %+v, want %+v", emitted, original)
+	}
+
+	// Emitting the same data again should not change the output
+	writerAgain := &jsonLinesMockedWriter{}
+	f = &XMLFormatter{config: &Config{Writer: writerAgain}}
+	err = f.Format(&TemplateData{NMAPRun: emitted}, "")
+	if err != nil {
+		t.Fatalf("XMLFormatter.Format() error = %v", err)
+	}
+	if !bytes.Equal(writer.data, writerAgain.data) {
+		t.Errorf("XMLFormatter.Format() output is not stable:\n%s\nwant:\n%s", writerAgain.data, writer.data)
+	}
+}
+
+func TestXMLFormatter_Format(t *testing.T) {
+	tests := []struct {
+		name       string
+		td         *TemplateData
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name: "Header with stylesheet",
+			td: &TemplateData{
+				NMAPRun: NMAPRun{
+					Scanner:       "nmap",
+					Args:          "nmap -p 22 <target>",
+					XMLStylesheet: `href="nmap.xsl" type="text/xsl"`,
+					Host: []Host{
+						{
+							Status:      HostStatus{State: "up", Reason: "user-set"},
+							HostAddress: []HostAddress{{Address: "127.0.0.1", AddressType: "ipv4"}},
+							Port: []Port{
+								{
+									Protocol: "tcp",
+									PortID:   22,
+									State:    PortState{State: "open", Reason: "syn-ack", ReasonTTL: "64"},
+									Service:  PortService{Name: "ssh", Method: "table", Conf: "3"},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<?xml-stylesheet href="nmap.xsl" type="text/xsl"?>
+<nmaprun scanner="nmap" args="nmap -p 22 &lt;target&gt;" start="0" startstr="" version="" xmloutputversion="1.05">
+  <scaninfo type="" protocol="" numservices="0" services=""></scaninfo>
+  <verbose level="0"></verbose>
+  <debugging level="0"></debugging>
+  <host>
+    <status state="up" reason="user-set"></status>
+    <address addr="127.0.0.1" addrtype="ipv4"></address>
+    <ports>
+      <port protocol="tcp" portid="22">
+        <state state="open" reason="syn-ack" reason_ttl="64"></state>
+        <service name="ssh" method="table" conf="3"></service>
+      </port>
+    </ports>
+  </host>
+  <runstats>
+    <finished time="0" timestr="" elapsed="0" summary="" exit=""></finished>
+    <hosts up="0" down="0" total="0"></hosts>
+  </runstats>
+</nmaprun>
+`,
+		},
+		{
+			name: "Port without service and output version of the input",
+			td: &TemplateData{
+				NMAPRun: NMAPRun{
+					Scanner:          "nmap",
+					XMLOutputVersion: "1.04",
+					Host: []Host{
+						{
+							Status:      HostStatus{State: "up"},
+							HostAddress: []HostAddress{{Address: "127.0.0.1", AddressType: "ipv4"}},
+							Port:        []Port{{Protocol: "tcp", PortID: 4444, State: PortState{State: "closed"}}},
+						},
+					},
+				},
+			},
+			wantOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE nmaprun>
+<nmaprun scanner="nmap" args="" start="0" startstr="" version="" xmloutputversion="1.04">
+  <scaninfo type="" protocol="" numservices="0" services=""></scaninfo>
+  <verbose level="0"></verbose>
+  <debugging level="0"></debugging>
+  <host>
+    <status state="up" reason=""></status>
+    <address addr="127.0.0.1" addrtype="ipv4"></address>
+    <ports>
+      <port protocol="tcp" portid="4444">
+        <state state="closed" reason="" reason_ttl=""></state>
+      </port>
+    </ports>
+  </host>
+  <runstats>
+    <finished time="0" timestr="" elapsed="0" summary="" exit=""></finished>
+    <hosts up="0" down="0" total="0"></hosts>
+  </runstats>
+</nmaprun>
+`,
+		},
+		{
+			name:    "Error",
+			td:      &TemplateData{},
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &XMLFormatter{config: &Config{Writer: writer}}
+			if err := f.Format(tt.td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("XMLFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("XMLFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
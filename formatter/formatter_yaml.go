@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// YAMLFormatter is struct defined for YAML Output use-case, it contains the same data as JSONFormatter
+type YAMLFormatter struct {
+	config *Config
+}
+
+// Format the data to YAML and output it to appropriate io.Writer
+func (f *YAMLFormatter) Format(td *TemplateData, templateContent string) (err error) {
+	encoder := yaml.NewEncoder(f.config.Writer)
+	encoder.SetIndent(2)
+
+	documents := []interface{}{td.NMAPRun}
+	if td.OutputOptions.YAMLOptions.MultiDocument {
+		documents = []interface{}{}
+		for i := range td.NMAPRun.Host {
+			documents = append(documents, td.NMAPRun.Host[i])
+		}
+	}
+
+	for _, d := range documents {
+		node, err := yamlNode(d, td.OutputOptions.YAMLOptions.SnakeCase)
+		if err != nil {
+			return err
+		}
+		err = encoder.Encode(node)
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+// yamlNode converts the value to YAML node with the same keys (and key order) as in JSON output,
+// JSON is a subset of YAML, so the value is encoded to JSON first and then parsed as YAML
+func yamlNode(v interface{}, snakeCase bool) (*yaml.Node, error) {
+	buf := new(bytes.Buffer)
+	var err error
+	if snakeCase {
+		err = newSnakeCaseEncoder(buf, false).Encode(v)
+	} else {
+		err = json.NewEncoder(buf).Encode(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	node := &yaml.Node{}
+	err = yaml.Unmarshal(buf.Bytes(), node)
+	if err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(node)
+	return node, nil
+}
+
+// resetYAMLStyle removes JSON (flow & quoted) style from all nodes, so block style is used instead
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+// defaultTemplateContent does not return anything in this case
+func (f *YAMLFormatter) defaultTemplateContent() string {
+	return ""
+}
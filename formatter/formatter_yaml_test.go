@@ -0,0 +1,205 @@
+package formatter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestYAMLFormatter_Format(t *testing.T) {
+	run := NMAPRun{
+		Scanner: "nmap",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{
+					{Address: "127.0.0.1", AddressType: "ipv4"},
+				},
+				Status: HostStatus{State: "up"},
+				Port: []Port{
+					{
+						Protocol: "tcp",
+						PortID:   80,
+						State:    PortState{State: "open"},
+						Service:  PortService{Name: "http", Version: "1.21"},
+					},
+				},
+			},
+			{
+				HostAddress: []HostAddress{
+					{Address: "127.0.0.2", AddressType: "ipv4"},
+				},
+				Status: HostStatus{State: "down"},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    YAMLOutputOptions
+		err        error
+		wantErr    bool
+		wantOutput string
+	}{
+		{
+			name:    "Multiple documents (snake_case)",
+			options: YAMLOutputOptions{SnakeCase: true, MultiDocument: true},
+			wantOutput: `start_time: 0
+end_time: 0
+port:
+  - protocol: tcp
+    port_id: 80
+    state:
+      state: open
+      reason: ""
+      reason_ttl: ""
+    service:
+      name: http
+      product: ""
+      version: "1.21"
+      extra_info: ""
+      method: ""
+      conf: ""
+      cpe: null
+    script: null
+host_address:
+  - address: 127.0.0.1
+    address_type: ipv4
+    vendor: ""
+host_names:
+  host_name: null
+status:
+  state: up
+  reason: ""
+os:
+  osport_used: null
+  osclass: null
+  osmatch: null
+trace:
+  port: 0
+  protocol: ""
+  hops: null
+uptime:
+  seconds: 0
+  last_boot: ""
+distance:
+  value: 0
+tcpsequence:
+  index: ""
+  difficulty: ""
+  values: ""
+ipidsequence:
+  class: ""
+  values: ""
+tcptssequence:
+  class: ""
+  values: ""
+---
+start_time: 0
+end_time: 0
+port: null
+host_address:
+  - address: 127.0.0.2
+    address_type: ipv4
+    vendor: ""
+host_names:
+  host_name: null
+status:
+  state: down
+  reason: ""
+os:
+  osport_used: null
+  osclass: null
+  osmatch: null
+trace:
+  port: 0
+  protocol: ""
+  hops: null
+uptime:
+  seconds: 0
+  last_boot: ""
+distance:
+  value: 0
+tcpsequence:
+  index: ""
+  difficulty: ""
+  values: ""
+ipidsequence:
+  class: ""
+  values: ""
+tcptssequence:
+  class: ""
+  values: ""
+`,
+		},
+		{
+			name:    "Error",
+			err:     errors.New("some error happened"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &jsonLinesMockedWriter{err: tt.err}
+			f := &YAMLFormatter{
+				config: &Config{
+					Writer: writer,
+				},
+			}
+			td := &TemplateData{
+				NMAPRun: run,
+				OutputOptions: OutputOptions{
+					YAMLOptions: tt.options,
+				},
+			}
+			if err := f.Format(td, ""); (err != nil) != tt.wantErr {
+				t.Errorf("YAMLFormatter.Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.err == nil && tt.wantOutput != string(writer.data) {
+				t.Errorf("YAMLFormatter.Format() output = \n%v, wantOutput = \n%v", string(writer.data), tt.wantOutput)
+			}
+		})
+	}
+}
+
+func TestYAMLFormatter_FormatSingleDocument(t *testing.T) {
+	writer := &jsonLinesMockedWriter{}
+	f := &YAMLFormatter{
+		config: &Config{
+			Writer: writer,
+		},
+	}
+	td := &TemplateData{
+		NMAPRun: NMAPRun{Scanner: "nmap", Args: "nmap -p 80"},
+	}
+	if err := f.Format(td, ""); err != nil {
+		t.Errorf("YAMLFormatter.Format() error = %v", err)
+	}
+	want := `Scanner: nmap
+Args: nmap -p 80
+Start: 0
+StartStr: ""
+Version: ""
+ScanInfo:
+  Type: ""
+  Protocol: ""
+  NumServices: 0
+  Services: ""
+Host: null
+Verbose:
+  Level: 0
+Debugging:
+  Level: 0
+RunStats:
+  Finished:
+    Time: 0
+    TimeStr: ""
+    Elapsed: 0
+    Summary: ""
+    Exit: ""
+  Hosts:
+    Up: 0
+    Down: 0
+    Total: 0
+`
+	if string(writer.data) != want {
+		t.Errorf("YAMLFormatter.Format() output = \n%v, want = \n%v", string(writer.data), want)
+	}
+}
@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	// Used to embed generated JSON schema files
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSONSchemaVersion is a version of JSON output structure, it has to be increased
+// every time NMAPRun (or any nested struct) changes and schema files are regenerated
+const JSONSchemaVersion = "1.1.0"
+
+// JSONSchemaDraft is a JSON Schema specification which is used for schema generation
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema variable is used to store generated JSON schema of NMAPRun with CamelCase keys
+//
+//go:embed resources/schema/nmaprun.schema.json
+var JSONSchema string
+
+// JSONSchemaSnakeCase variable is used to store generated JSON schema of NMAPRun with snake_case keys
+//
+//go:embed resources/schema/nmaprun.snake_case.schema.json
+var JSONSchemaSnakeCase string
+
+// JSONEnvelope wraps JSON output with meta-information about the schema, nmap-formatter version and options used
+type JSONEnvelope struct {
+	SchemaVersion string
+	ToolVersion   string
+	Options       JSONEnvelopeOptions
+	NMAPRun       NMAPRun
+}
+
+// JSONEnvelopeOptions contains options that were used to produce JSON output
+type JSONEnvelopeOptions struct {
+	SnakeCase         bool
+	SkipDownHosts     bool
+	FilterExpressions []string
+}
+
+// jsonSchemaGenerator builds JSON schema from Go types using reflection,
+// every struct is placed into `$defs` and referenced from its parent
+type jsonSchemaGenerator struct {
+	snakeCase bool
+	defs      map[string]interface{}
+}
+
+// GenerateJSONSchema generates JSON schema of NMAPRun struct, keys are converted to snake_case if requested
+func GenerateJSONSchema(snakeCase bool) ([]byte, error) {
+	g := &jsonSchemaGenerator{
+		snakeCase: snakeCase,
+		defs:      map[string]interface{}{},
+	}
+	root := g.schema(reflect.TypeOf(NMAPRun{}))
+	id := "urn:nmap-formatter:schema:nmaprun:" + JSONSchemaVersion
+	if snakeCase {
+		id = "urn:nmap-formatter:schema:nmaprun:snake_case:" + JSONSchemaVersion
+	}
+	schema := map[string]interface{}{
+		"$schema": JSONSchemaDraft,
+		"$id":     id,
+		"title":   "NMAPRun",
+		"$ref":    root["$ref"],
+		"$defs":   g.defs,
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// schema returns schema definition of the provided type
+func (g *jsonSchemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		// nil slices are encoded as null
+		return map[string]interface{}{
+			"type":  []string{"array", "null"},
+			"items": g.schema(t.Elem()),
+		}
+	case reflect.Struct:
+		return g.structRef(t)
+	}
+	panic(fmt.Sprintf("json schema: unsupported type %s", t))
+}
+
+// structRef defines struct in `$defs` (only once) and returns the reference to it
+func (g *jsonSchemaGenerator) structRef(t reflect.Type) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	if _, ok := g.defs[t.Name()]; ok {
+		return ref
+	}
+	// Placeholder prevents infinite recursion on self-referencing types
+	g.defs[t.Name()] = nil
+
+	properties := map[string]interface{}{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		name := field.Name
+		if g.snakeCase {
+			name = toSnakeCase(name)
+		}
+		properties[name] = g.schema(field.Type)
+		required = append(required, name)
+	}
+	g.defs[t.Name()] = map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	return ref
+}
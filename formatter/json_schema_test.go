@@ -0,0 +1,135 @@
+package formatter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// updateSchema regenerates JSON schema files: go test ./formatter -run TestGenerateJSONSchema -update-schema
+var updateSchema = flag.Bool("update-schema", false, "regenerate JSON schema files in resources/schema")
+
+func TestGenerateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		snakeCase bool
+		file      string
+		embedded  string
+	}{
+		{
+			name:      "CamelCase",
+			snakeCase: false,
+			file:      "resources/schema/nmaprun.schema.json",
+			embedded:  JSONSchema,
+		},
+		{
+			name:      "snake_case",
+			snakeCase: true,
+			file:      "resources/schema/nmaprun.snake_case.schema.json",
+			embedded:  JSONSchemaSnakeCase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateJSONSchema(tt.snakeCase)
+			if err != nil {
+				t.Fatalf("GenerateJSONSchema() error = %v", err)
+			}
+			if *updateSchema {
+				if err := os.WriteFile(tt.file, got, 0o644); err != nil {
+					t.Fatalf("could not write schema file %s: %v", tt.file, err)
+				}
+				return
+			}
+			if string(got) != tt.embedded {
+				t.Errorf(
+					"JSON schema %s is outdated, structs have changed: increase JSONSchemaVersion and run "+
+						"`go test ./formatter -run TestGenerateJSONSchema -update-schema`",
+					tt.file,
+				)
+			}
+		})
+	}
+}
+
+// jsonSchemaChecksums contains SHA-256 checksums of CamelCase and snake_case schema files of every
+// released JSONSchemaVersion, a new entry has to be added every time the version is increased
+var jsonSchemaChecksums = map[string][2]string{
+	"1.0.0": {
+		"931aae5546fb887f7750448c5397cb647b33b6778cf3de0a8bc42d1c1dd05057",
+		"e8333b067c5a7d84abb2b09559a3529f05f6b9934edb3daf64186360c57a26b5",
+	},
+	"1.1.0": {
+		"c5aa86be2851c892c6314ab3b5b1957dd0a4474b1f9d1bbe2ec7a0b3243cd1ff",
+		"6c81dea19b512722fe06826b1bd49d37a2ba3367454816dbfb98e075bdbadd72",
+	},
+}
+
+func TestJSONSchemaVersion(t *testing.T) {
+	want, ok := jsonSchemaChecksums[JSONSchemaVersion]
+	if !ok {
+		t.Fatalf("checksums of JSON schema %s are missing in jsonSchemaChecksums", JSONSchemaVersion)
+	}
+	got := [2]string{
+		fmt.Sprintf("%x", sha256.Sum256([]byte(JSONSchema))),
+		fmt.Sprintf("%x", sha256.Sum256([]byte(JSONSchemaSnakeCase))),
+	}
+	if got != want {
+		t.Errorf(
+			"JSON schema has changed without increasing JSONSchemaVersion %s: increase it, regenerate schema files "+
+				"and add checksums %v to jsonSchemaChecksums",
+			JSONSchemaVersion,
+			got,
+		)
+	}
+}
+
+func TestGenerateJSONSchema_matchesOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		snakeCase bool
+	}{
+		{name: "CamelCase", snakeCase: false},
+		{name: "snake_case", snakeCase: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GenerateJSONSchema(tt.snakeCase)
+			if err != nil {
+				t.Fatalf("GenerateJSONSchema() error = %v", err)
+			}
+			schema := struct {
+				Defs map[string]struct {
+					Properties map[string]interface{} `json:"properties"`
+				} `json:"$defs"`
+			}{}
+			if err := json.Unmarshal(data, &schema); err != nil {
+				t.Fatalf("could not parse generated schema: %v", err)
+			}
+
+			// Every key in the actual output has to be defined in the schema
+			buf := new(bytes.Buffer)
+			if tt.snakeCase {
+				err = newSnakeCaseEncoder(buf, false).Encode(NMAPRun{})
+			} else {
+				err = json.NewEncoder(buf).Encode(NMAPRun{})
+			}
+			if err != nil {
+				t.Fatalf("could not encode NMAPRun: %v", err)
+			}
+			output := map[string]interface{}{}
+			if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+				t.Fatalf("could not parse JSON output: %v", err)
+			}
+			for key := range output {
+				if _, ok := schema.Defs["NMAPRun"].Properties[key]; !ok {
+					t.Errorf("key %s is missing in the JSON schema", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	// NetworkNodeScanner is a type of the node that represents the scanner itself
+	NetworkNodeScanner = "scanner"
+	// NetworkNodeHop is a type of the node that represents traceroute hop (router)
+	NetworkNodeHop = "hop"
+	// NetworkNodeHost is a type of the node that represents scanned host
+	NetworkNodeHost = "host"
+	// NetworkNodeService is a type of the node that represents a service (port) on the host
+	NetworkNodeService = "service"
+
+	// NetworkEdgeRoute is a type of the edge that represents traceroute link
+	NetworkEdgeRoute = "route"
+	// NetworkEdgeService is a type of the edge between a host and its service
+	NetworkEdgeService = "service"
+)
+
+// networkGraphAttribute describes node attribute that is exported to graph formats
+type networkGraphAttribute struct {
+	name string
+	// kind is a type of the attribute value: string or int
+	kind string
+}
+
+// networkGraphNodeAttributes is an ordered list of all node attributes
+var networkGraphNodeAttributes = []networkGraphAttribute{
+	{"type", "string"},
+	{"label", "string"},
+	{"state", "string"},
+	{"os", "string"},
+	{"hostname", "string"},
+	{"service", "string"},
+	{"protocol", "string"},
+	{"port", "int"},
+	{"product", "string"},
+	{"version", "string"},
+}
+
+// networkGraph is a graph of the scanned network topology, it's used by graph formats (GraphML, GEXF).
+// Nodes are merged by IP address, so the router shared by multiple hosts is a single node
+type networkGraph struct {
+	nodes     []*networkNode
+	nodeIndex map[string]*networkNode
+	edges     []*networkEdge
+	edgeIndex map[string]*networkEdge
+}
+
+// networkNode is a typed node with attributes (networkGraphNodeAttributes)
+type networkNode struct {
+	id         string
+	attributes map[string]string
+}
+
+// networkEdge is a directed edge, route edges have RTT weight
+type networkEdge struct {
+	id       string
+	source   string
+	target   string
+	kind     string
+	weight   float64
+	hasRoute bool
+}
+
+// newNetworkGraph builds a network graph from NMAPRun: scanner -> hops -> hosts -> services
+func newNetworkGraph(n *NMAPRun) *networkGraph {
+	g := &networkGraph{
+		nodeIndex: map[string]*networkNode{},
+		edgeIndex: map[string]*networkEdge{},
+	}
+	g.node(NetworkNodeScanner, map[string]string{"type": NetworkNodeScanner, "label": n.Scanner})
+
+	// Hosts are added first, so hops with the same IP are merged into host nodes
+	hostIDs := make([]string, len(n.Host))
+	for i := range n.Host {
+		hostIDs[i] = g.addHost(i, &n.Host[i])
+	}
+	for i := range n.Host {
+		g.addRoute(hostIDs[i], &n.Host[i])
+	}
+	return g
+}
+
+// addHost adds host node with all its services and returns the ID of the host node
+func (g *networkGraph) addHost(index int, h *Host) string {
+	id := networkHostNodeID(index, h)
+	attributes := map[string]string{
+		"type":     NetworkNodeHost,
+		"label":    h.JoinedAddresses("/"),
+		"state":    h.Status.State,
+		"hostname": h.JoinedHostNames("/"),
+	}
+	if len(h.OS.OSMatch) > 0 {
+		attributes["os"] = h.OS.OSMatch[0].Name
+	}
+	g.node(id, attributes)
+
+	for i := range h.Port {
+		port := &h.Port[i]
+		serviceID := fmt.Sprintf("%s/%s/%d", id, port.Protocol, port.PortID)
+		g.node(serviceID, map[string]string{
+			"type":     NetworkNodeService,
+			"label":    fmt.Sprintf("%d/%s %s", port.PortID, port.Protocol, port.Service.Name),
+			"state":    port.State.State,
+			"service":  port.Service.Name,
+			"protocol": port.Protocol,
+			"port":     strconv.Itoa(port.PortID),
+			"product":  port.Service.Product,
+			"version":  port.Service.Version,
+		})
+		g.edge(id, serviceID, NetworkEdgeService, 0, false)
+	}
+	return id
+}
+
+// addRoute adds traceroute path from the scanner to the host, the last hop is the host itself
+func (g *networkGraph) addRoute(hostID string, h *Host) {
+	previous := NetworkNodeScanner
+	hops := h.Trace.Hops
+	for i := range hops {
+		target := hostID
+		if i != len(hops)-1 {
+			target = "ip:" + hops[i].IPAddr
+			g.node(target, map[string]string{
+				"type":     NetworkNodeHop,
+				"label":    hops[i].IPAddr,
+				"hostname": hops[i].Host,
+			})
+		}
+		g.edge(previous, target, NetworkEdgeRoute, float64(hops[i].RTT), true)
+		previous = target
+	}
+	if len(hops) == 0 {
+		g.edge(NetworkNodeScanner, hostID, NetworkEdgeRoute, 0, true)
+	}
+}
+
+// node adds new node or merges attributes into already existing node with the same ID,
+// host attributes take preference over hop attributes
+func (g *networkGraph) node(id string, attributes map[string]string) {
+	existing, ok := g.nodeIndex[id]
+	if !ok {
+		node := &networkNode{id: id, attributes: map[string]string{}}
+		for k, v := range attributes {
+			if v != "" {
+				node.attributes[k] = v
+			}
+		}
+		g.nodes = append(g.nodes, node)
+		g.nodeIndex[id] = node
+		return
+	}
+	for k, v := range attributes {
+		if _, exists := existing.attributes[k]; !exists && v != "" {
+			existing.attributes[k] = v
+		}
+	}
+}
+
+// edge adds new edge, the same link found in multiple traceroutes is added only once
+func (g *networkGraph) edge(source string, target string, kind string, weight float64, hasRoute bool) {
+	key := source + "->" + target
+	if _, ok := g.edgeIndex[key]; ok {
+		return
+	}
+	e := &networkEdge{
+		id:       fmt.Sprintf("e%d", len(g.edges)),
+		source:   source,
+		target:   target,
+		kind:     kind,
+		weight:   weight,
+		hasRoute: hasRoute,
+	}
+	g.edges = append(g.edges, e)
+	g.edgeIndex[key] = e
+}
+
+// networkHostNodeID returns node ID of the host based on its first IP address
+func networkHostNodeID(index int, h *Host) string {
+	for i := range h.HostAddress {
+		if h.HostAddress[i].AddressType != "mac" {
+			return "ip:" + h.HostAddress[i].Address
+		}
+	}
+	return fmt.Sprintf("host:%d", index)
+}
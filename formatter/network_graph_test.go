@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_newNetworkGraph(t *testing.T) {
+	// Two hosts behind the same router, the first host is also a traceroute hop of the second host,
+	// the third host is down
+	run := NMAPRun{
+		Scanner: "nmap",
+		Args:    "nmap -sV --traceroute 192.168.1.0/24",
+		Host: []Host{
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.10", AddressType: "ipv4"}},
+				HostNames:   HostNames{HostName: []HostName{{Name: "web.local"}}},
+				Status:      HostStatus{State: "up"},
+				OS:          OS{OSMatch: []OSMatch{{Name: "Linux 5.0 - 5.4"}}},
+				Port:        []Port{{Protocol: "tcp", PortID: 80, State: PortState{State: "open"}, Service: PortService{Name: "http", Product: "nginx", Version: "1.18.0"}}},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.5}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.25}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.20", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "up"},
+				Trace:       Trace{Hops: []Hop{{TTL: 1, IPAddr: "10.0.0.1", RTT: 0.75}, {TTL: 2, IPAddr: "192.168.1.10", RTT: 1.5}, {TTL: 3, IPAddr: "192.168.1.20", RTT: 2}}},
+			},
+			{
+				HostAddress: []HostAddress{{Address: "192.168.1.30", AddressType: "ipv4"}},
+				Status:      HostStatus{State: "down"},
+			},
+		},
+	}
+	g := newNetworkGraph(&run)
+
+	var nodes []string
+	for _, n := range g.nodes {
+		nodes = append(nodes, n.id+":"+n.attributes["type"])
+	}
+	wantNodes := []string{
+		"scanner:scanner",
+		"ip:192.168.1.10:host",
+		"ip:192.168.1.10/tcp/80:service",
+		"ip:192.168.1.20:host",
+		"ip:192.168.1.30:host",
+		"ip:10.0.0.1:hop",
+	}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		t.Errorf("newNetworkGraph() nodes = %v, want %v", nodes, wantNodes)
+	}
+
+	var edges []networkEdge
+	for _, e := range g.edges {
+		edges = append(edges, *e)
+	}
+	wantEdges := []networkEdge{
+		{id: "e0", source: "ip:192.168.1.10", target: "ip:192.168.1.10/tcp/80", kind: NetworkEdgeService},
+		{id: "e1", source: "scanner", target: "ip:10.0.0.1", kind: NetworkEdgeRoute, weight: 0.5, hasRoute: true},
+		{id: "e2", source: "ip:10.0.0.1", target: "ip:192.168.1.10", kind: NetworkEdgeRoute, weight: 1.25, hasRoute: true},
+		{id: "e3", source: "ip:192.168.1.10", target: "ip:192.168.1.20", kind: NetworkEdgeRoute, weight: 2, hasRoute: true},
+		{id: "e4", source: "scanner", target: "ip:192.168.1.30", kind: NetworkEdgeRoute, hasRoute: true},
+	}
+	if !reflect.DeepEqual(edges, wantEdges) {
+		t.Errorf("newNetworkGraph() edges = %+v, want %+v", edges, wantEdges)
+	}
+
+	wantHost := map[string]string{
+		"type":     NetworkNodeHost,
+		"label":    "192.168.1.10",
+		"state":    "up",
+		"os":       "Linux 5.0 - 5.4",
+		"hostname": "web.local",
+	}
+	if got := g.nodeIndex["ip:192.168.1.10"].attributes; !reflect.DeepEqual(got, wantHost) {
+		t.Errorf("newNetworkGraph() host attributes = %v, want %v", got, wantHost)
+	}
+}
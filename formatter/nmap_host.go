@@ -23,6 +23,53 @@ type Host struct {
 	TCPTSSequence TCPTSSequence `xml:"tcptssequence"`
 }
 
+// MarshalXML encodes Host back to nmap XML, child nodes are ordered the same way as nmap does
+// and absent attributes (starttime and endtime of down hosts) and empty optional nodes
+// (hostnames, os, uptime, trace, et cetera) are skipped
+func (h Host) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = nil
+	if h.StartTime != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "starttime"}, Value: strconv.Itoa(h.StartTime)})
+	}
+	if h.EndTime != 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "endtime"}, Value: strconv.Itoa(h.EndTime)})
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	ports := struct {
+		Port []Port `xml:"port"`
+	}{h.Port}
+	elements := []struct {
+		name  string
+		value interface{}
+		empty bool
+	}{
+		{"status", h.Status, false},
+		{"address", h.HostAddress, false},
+		{"hostnames", h.HostNames, len(h.HostNames.HostName) == 0},
+		{"ports", ports, len(h.Port) == 0},
+		{"os", h.OS, len(h.OS.OSPortUsed) == 0 && len(h.OS.OSClass) == 0 && len(h.OS.OSMatch) == 0},
+		{"uptime", h.Uptime, h.Uptime == Uptime{}},
+		{"distance", h.Distance, h.Distance == Distance{}},
+		{"tcpsequence", h.TCPSequence, h.TCPSequence == TCPSequence{}},
+		{"ipidsequence", h.IPIDSequence, h.IPIDSequence == IPIDSequence{}},
+		{"tcptssequence", h.TCPTSSequence, h.TCPTSSequence == TCPTSSequence{}},
+		{"trace", h.Trace, len(h.Trace.Hops) == 0 && h.Trace.Port == 0 && h.Trace.Protocol == ""},
+	}
+	for _, el := range elements {
+		if el.empty {
+			continue
+		}
+		err = e.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}})
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
 // JoinedAddresses joins all possible host addresses with a delimiter string
 func (h *Host) JoinedAddresses(delimiter string) string {
 	addr := ""
@@ -95,7 +142,7 @@ func (hs *HostStatus) IsUp() bool {
 type HostAddress struct {
 	Address     string `xml:"addr,attr"`
 	AddressType string `xml:"addrtype,attr"`
-	Vendor      string `xml:"vendor,attr"`
+	Vendor      string `xml:"vendor,attr,omitempty"`
 }
 
 // HostNames struct contains list of hostnames (domains) that this host has
@@ -116,6 +163,18 @@ type OS struct {
 	OSMatch    []OSMatch    `xml:"osmatch"`
 }
 
+// firstOSClass returns OS class of the best OS match, older nmap versions
+// have OS classes outside of OS matches
+func (o *OS) firstOSClass() (OSClass, bool) {
+	if len(o.OSMatch) > 0 && len(o.OSMatch[0].OSClass) > 0 {
+		return o.OSMatch[0].OSClass[0], true
+	}
+	if len(o.OSClass) > 0 {
+		return o.OSClass[0], true
+	}
+	return OSClass{}, false
+}
+
 // OSPortUsed defines which ports were used for OS detection
 type OSPortUsed struct {
 	State    string `xml:"state,attr"`
@@ -128,22 +187,23 @@ type OSClass struct {
 	Type     string   `xml:"type,attr"`
 	Vendor   string   `xml:"vendor,attr"`
 	OSFamily string   `xml:"osfamily,attr"`
-	OSGen    string   `xml:"osgen,attr"`
+	OSGen    string   `xml:"osgen,attr,omitempty"`
 	Accuracy string   `xml:"accuracy,attr"`
 	CPE      []string `xml:"cpe"`
 }
 
 // OSMatch is a record of OS that matched with certain accuracy
 type OSMatch struct {
-	Name     string `xml:"name,attr"`
-	Accuracy string `xml:"accuracy,attr"`
-	Line     string `xml:"line,attr"`
+	Name     string    `xml:"name,attr"`
+	Accuracy string    `xml:"accuracy,attr"`
+	Line     string    `xml:"line,attr"`
+	OSClass  []OSClass `xml:"osclass"`
 }
 
 // Trace struct contains trace information with hops
 type Trace struct {
-	Port     int    `xml:"port,attr"`
-	Protocol string `xml:"proto,attr"`
+	Port     int    `xml:"port,attr,omitempty"`
+	Protocol string `xml:"proto,attr,omitempty"`
 	Hops     []Hop  `xml:"hop"`
 }
 
@@ -152,7 +212,7 @@ type Hop struct {
 	TTL    int    `xml:"ttl,attr"`
 	IPAddr string `xml:"ipaddr,attr"`
 	RTT    RTT    `xml:"rtt,attr"`
-	Host   string `xml:"host,attr"`
+	Host   string `xml:"host,attr,omitempty"`
 }
 
 // RTT is a separate type that is located in Hop struct
@@ -168,3 +228,8 @@ func (r *RTT) UnmarshalXMLAttr(attr xml.Attr) error {
 	*(*float64)(r) = value
 	return nil
 }
+
+// MarshalXMLAttr encodes RTT value the same way as it's parsed
+func (r RTT) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: strconv.FormatFloat(float64(r), 'f', -1, 64)}, nil
+}
@@ -1,5 +1,7 @@
 package formatter
 
+import "encoding/xml"
+
 // Port record contains main information about port that was scanned
 type Port struct {
 	Protocol string      `xml:"protocol,attr"`
@@ -19,14 +21,25 @@ type PortState struct {
 // PortService struct contains information about the service that is located on certain port
 type PortService struct {
 	Name      string   `xml:"name,attr"`
-	Product   string   `xml:"product,attr"`
-	Version   string   `xml:"version,attr"`
-	ExtraInfo string   `xml:"extrainfo,attr"`
+	Product   string   `xml:"product,attr,omitempty"`
+	Version   string   `xml:"version,attr,omitempty"`
+	ExtraInfo string   `xml:"extrainfo,attr,omitempty"`
 	Method    string   `xml:"method,attr"`
 	Conf      string   `xml:"conf,attr"`
 	CPE       []string `xml:"cpe"`
 }
 
+// MarshalXML skips `service` node if the port had no service information,
+// so re-emitted nmap XML does not contain data which was not in the input
+func (s PortService) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if s.Name == "" && s.Product == "" && s.Version == "" && s.ExtraInfo == "" && s.Method == "" && s.Conf == "" && len(s.CPE) == 0 {
+		return nil
+	}
+	// portService has the same fields, but no MarshalXML method, so it's encoded with the struct tags
+	type portService PortService
+	return e.EncodeElement(portService(s), start)
+}
+
 // ExtraPorts contains information about certain amount of ports that were (for example) filtered
 type ExtraPorts struct {
 	State string `xml:"state,attr"`
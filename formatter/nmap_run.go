@@ -1,5 +1,10 @@
 package formatter
 
+import (
+	"encoding/xml"
+	"strconv"
+)
+
 // NMAPRun represents main `<nmaprun>` node which contains meta-information about the scan
 // For example: scanner, what arguments used during scan, nmap version, verbosity level, et cetera
 // Main information about scanned hosts is in the `host` node
@@ -14,6 +19,61 @@ type NMAPRun struct {
 	Verbose   Verbose   `xml:"verbose"`
 	Debugging Debugging `xml:"debugging"`
 	RunStats  RunStats  `xml:"runstats"`
+	// XMLStylesheet is a raw content of `<?xml-stylesheet?>` instruction which precedes `<nmaprun>` node,
+	// it's kept only to re-emit nmap XML, so it's not part of any other output
+	XMLStylesheet string `xml:"-" json:"-"`
+	// XMLOutputVersion is a version of nmap XML output format of the input, it's kept only to re-emit nmap XML
+	XMLOutputVersion string `xml:"xmloutputversion,attr" json:"-"`
+}
+
+// NMAPXMLOutputVersion is a version of nmap XML output format that is emitted by XMLFormatter,
+// if the version of the input is unknown
+const NMAPXMLOutputVersion = "1.05"
+
+// MarshalXML encodes NMAPRun back to nmap XML, child nodes are ordered the same way as nmap does
+func (n NMAPRun) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "nmaprun"}
+	xmlOutputVersion := n.XMLOutputVersion
+	if xmlOutputVersion == "" {
+		xmlOutputVersion = NMAPXMLOutputVersion
+	}
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "scanner"}, Value: n.Scanner},
+		{Name: xml.Name{Local: "args"}, Value: n.Args},
+		{Name: xml.Name{Local: "start"}, Value: strconv.Itoa(n.Start)},
+		{Name: xml.Name{Local: "startstr"}, Value: n.StartStr},
+		{Name: xml.Name{Local: "version"}, Value: n.Version},
+		{Name: xml.Name{Local: "xmloutputversion"}, Value: xmlOutputVersion},
+	}
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+	elements := []struct {
+		name  string
+		value interface{}
+	}{
+		{"scaninfo", n.ScanInfo},
+		{"verbose", n.Verbose},
+		{"debugging", n.Debugging},
+	}
+	for _, el := range elements {
+		err = e.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}})
+		if err != nil {
+			return err
+		}
+	}
+	for i := range n.Host {
+		err = e.EncodeElement(n.Host[i], xml.StartElement{Name: xml.Name{Local: "host"}})
+		if err != nil {
+			return err
+		}
+	}
+	err = e.EncodeElement(n.RunStats, xml.StartElement{Name: xml.Name{Local: "runstats"}})
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
 }
 
 // ScanInfo shows what type of scan it was and number of services covered
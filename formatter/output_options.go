@@ -5,10 +5,23 @@ type OutputOptions struct {
 	HTMLOptions         HTMLOutputOptions
 	MarkdownOptions     MarkdownOutputOptions
 	JSONOptions         JSONOutputOptions
+	JSONLinesOptions    JSONLinesOutputOptions
+	YAMLOptions         YAMLOutputOptions
 	CSVOptions          CSVOutputOptions
 	SqliteOutputOptions SqliteOutputOptions
 	ExcelOptions        ExcelOutputOptions
 	D2LangOptions       D2LangOutputOptions
+	PDFOptions          PDFOutputOptions
+	MermaidOptions      MermaidOutputOptions
+	ECSOptions          ECSOutputOptions
+	PrometheusOptions   PrometheusOutputOptions
+	AnsibleOptions      AnsibleOutputOptions
+	ParquetOptions      ParquetOutputOptions
+	SQLOptions          SQLOutputOptions
+	LaTeXOptions        LaTeXOutputOptions
+	AsciiDocOptions     AsciiDocOutputOptions
+	TableOptions        TableOutputOptions
+	DotOptions          DotOutputOptions
 }
 
 // HTMLOutputOptions stores options related only to HTML conversion/formatting
@@ -25,6 +38,8 @@ type HTMLOutputOptions struct {
 	SkipMetrics bool
 	// SkipPortScripts skips port scripts information for HTML
 	SkipPortScripts bool
+	// SkipTopology skips topology diagram, rendering of the diagram may take a while for huge scans
+	SkipTopology bool
 	// DarkMode sets a style to be mostly in dark colours, if false, light colours would be used
 	DarkMode bool
 	// FloatingContentsTable is an option to make contents table float on the side of the page
@@ -53,10 +68,35 @@ type JSONOutputOptions struct {
 	PrettyPrint bool
 	// SnakeCase converts JSON keys from CamelCase to snake_case
 	SnakeCase bool
+	// Envelope wraps JSON output with schema version, nmap-formatter version and options used (JSONEnvelope)
+	Envelope bool
+}
+
+// JSONLinesOutputOptions store options related only to JSON Lines conversion/formatting,
+// JSONOutputOptions.SnakeCase is respected as well
+type JSONLinesOutputOptions struct {
+	// Record defines what every line represents: a host (JSONLinesHostRecord) or a port (JSONLinesPortRecord)
+	Record string
+}
+
+// YAMLOutputOptions store options related only to YAML conversion/formatting
+type YAMLOutputOptions struct {
+	// SnakeCase converts YAML keys from CamelCase to snake_case
+	SnakeCase bool
+	// MultiDocument outputs every host as a separate YAML document
+	MultiDocument bool
 }
 
 // CSVOutputOptions store option related only to CSV conversion/formatting
 type CSVOutputOptions struct {
+	// Flat outputs one row per port, every row repeats host data (IP, hostnames, et cetera)
+	Flat bool
+	// Delimiter is a single character used to separate fields, by default it's a comma
+	Delimiter string
+	// Columns is a list of columns to output, by default CSVDefaultColumns are used
+	Columns []string
+	// SkipHeader skips the header row of the CSV output
+	SkipHeader bool
 }
 
 // SqliteOutputOptions store options related to SQLite database formatting
@@ -74,4 +114,159 @@ type ExcelOutputOptions struct {
 
 // D2LangOutputOptions store options related to D2 language file formatting
 type D2LangOutputOptions struct {
+	// SVG renders SVG image with D2 library instead of D2 language file
+	SVG bool
+	// Layout is a layout engine (dagre, elk), by default it's D2DagreLayout
+	Layout string
+	// ThemeID is an identifier of D2 theme, 0 is a default (neutral) theme
+	ThemeID int64
+	// Direction is a direction of the diagram (up, down, left, right), by default it's down
+	Direction string
+	// SkipClosedPorts leaves out closed ports
+	SkipClosedPorts bool
+	// SkipFilteredPorts leaves out filtered ports (incl. open|filtered and closed|filtered)
+	SkipFilteredPorts bool
+	// GroupBySubnet groups hosts into containers by their subnet
+	GroupBySubnet bool
+	// SubnetPrefix is IPv4 prefix length of the containers (see subnetPrefix)
+	SubnetPrefix int
+}
+
+// PDFOutputOptions store options related to PDF document formatting
+type PDFOutputOptions struct {
+	// SkipHeader skips the cover page of the PDF document
+	SkipHeader bool
+	// SkipTOC skips the table of contents in the PDF document
+	SkipTOC bool
+	// SkipSummary skips general summary for PDF
+	SkipSummary bool
+	// SkipTraceroute skips traceroute information for PDF
+	SkipTraceroute bool
+	// SkipMetrics skips metrics related data for PDF
+	SkipMetrics bool
+	// SkipPortScripts skips port scripts information for PDF
+	SkipPortScripts bool
+}
+
+// MermaidOutputOptions store options related to Mermaid diagram formatting
+type MermaidOutputOptions struct {
+	// Direction is a flowchart direction (TB, TD, BT, RL, LR), by default it's MermaidDefaultDirection
+	Direction string
+	// GroupBySubnet groups hosts into subgraphs by their subnet
+	GroupBySubnet bool
+	// SubnetPrefix is IPv4 prefix length of the subgraphs (see subnetPrefix)
+	SubnetPrefix int
+}
+
+// ECSOutputOptions store options related to Elastic Common Schema (ECS) bulk formatting
+type ECSOutputOptions struct {
+	// Index is Elasticsearch/OpenSearch index name used in bulk actions, by default it's ECSDefaultIndex
+	Index string
+	// URL is `_bulk` API endpoint, if it's provided documents are sent there instead of the output
+	URL string
+	// BatchSize is an amount of documents sent in one request, by default it's ECSDefaultBatchSize
+	BatchSize int
+	// Retries is an amount of retries of failed request (network error, 429 or 5xx status)
+	Retries int
+}
+
+// PrometheusOutputOptions store options related to Prometheus metrics formatting
+type PrometheusOutputOptions struct {
+	// Labels are constant labels added to every metric (for example: site, job)
+	Labels map[string]string
+	// HostLabels identify the host in per-host metrics (address, hostname, mac, os),
+	// by default PrometheusDefaultHostLabels are used
+	HostLabels []string
+}
+
+// AnsibleOutputOptions store options related to Ansible inventory formatting
+type AnsibleOutputOptions struct {
+	// Format is an inventory format: AnsibleINIFormat (default) or AnsibleYAMLFormat
+	Format string
+	// SubnetPrefix is IPv4 prefix length of `subnet_*` groups (see subnetPrefix)
+	SubnetPrefix int
+	// Groups are custom groups defined as `name=expression`, expression syntax is the same as in filter
+	Groups []string
+}
+
+// ParquetOutputOptions store options related to Apache Parquet formatting
+type ParquetOutputOptions struct {
+	// Table is a table written to the output (hosts, ports, scripts, os_matches), by default it's ParquetPortsTable
+	Table string
+	// Directory is a directory where all tables are written as `<table>.parquet` files, output is not used in this case
+	Directory string
+}
+
+// SQLOutputOptions store options related to SQL dump formatting
+type SQLOutputOptions struct {
+	// Dialect is a SQL dialect of the script (postgres, mysql, sqlite), by default it's SQLPostgresDialect
+	Dialect string
+}
+
+// LaTeXOutputOptions stores options related only to LaTeX conversion/formatting
+type LaTeXOutputOptions struct {
+	// SkipHeader skips the title of the document
+	SkipHeader bool
+	// SkipTOC skips the table of contents
+	SkipTOC bool
+	// SkipSummary skips general summary for LaTeX
+	SkipSummary bool
+	// SkipPortScripts skips port scripts information for LaTeX
+	SkipPortScripts bool
+	// SkipTraceroute skips traceroute information for LaTeX
+	SkipTraceroute bool
+	// SkipMetrics skips metrics related data for LaTeX
+	SkipMetrics bool
+}
+
+// AsciiDocOutputOptions stores options related only to AsciiDoc conversion/formatting
+type AsciiDocOutputOptions struct {
+	// SkipHeader skips the document title
+	SkipHeader bool
+	// SkipTOC skips the table of contents
+	SkipTOC bool
+	// SkipSummary skips general summary for AsciiDoc
+	SkipSummary bool
+	// SkipPortScripts skips port scripts information for AsciiDoc
+	SkipPortScripts bool
+	// SkipTraceroute skips traceroute information for AsciiDoc
+	SkipTraceroute bool
+	// SkipMetrics skips metrics related data for AsciiDoc
+	SkipMetrics bool
+}
+
+// TableOutputOptions stores options related only to terminal table output
+type TableOutputOptions struct {
+	// Color is a color mode (auto, always, never), by default colors are enabled only for a terminal
+	Color string
+	// Width is a maximum width of the table, 0 means terminal width
+	Width int
+	// Overflow defines what to do with values that don't fit the width (truncate, wrap)
+	Overflow string
+}
+
+// DotOutputOptions stores options related only to Graphviz (dot) formatting
+type DotOutputOptions struct {
+	// Layout is a Graphviz layout engine (dot, neato, fdp, sfdp, etc.), by default it's DotLayout
+	Layout string
+	// RankDir is a direction of the graph (TB, LR, BT, RL), Graphviz default is used if it's empty
+	RankDir string
+	// Font is a font name used for all labels, by default it's DotFontStyle
+	Font string
+	// OpenPortColor is a color of open ports in graphs and tables, by default it's DotOpenPortColor
+	OpenPortColor string
+	// FilteredPortColor is a color of filtered ports in graphs and tables, by default it's DotFilteredPortColor
+	FilteredPortColor string
+	// ClosedPortColor is a color of closed ports in graphs and tables, by default it's DotClosedPortColor
+	ClosedPortColor string
+	// DefaultColor is a color of ports with other states, by default it's DotDefaultColor
+	DefaultColor string
+	// HideClosedPorts leaves out closed ports
+	HideClosedPorts bool
+	// GroupBySubnet groups hosts into clusters by their subnet
+	GroupBySubnet bool
+	// SubnetPrefix is IPv4 prefix length of the clusters (see subnetPrefix)
+	SubnetPrefix int
+	// SkipTraceroute leaves out traceroute hops, scanner is connected to the hosts directly
+	SkipTraceroute bool
 }
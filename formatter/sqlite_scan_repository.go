@@ -84,10 +84,15 @@ func (s *ScanRepository) insertScan(n *NMAPRun) error {
 
 // getScanIdentifier returns a unique string provided either by a user or generates new random uuid
 func (s *ScanRepository) getScanIdentifier() string {
-	if s.config.OutputOptions.SqliteOutputOptions.ScanIdentifier == "" {
+	return scanIdentifier(&s.config.OutputOptions)
+}
+
+// scanIdentifier returns a scan identifier passed by the user (`--scan-id`) or generates new random uuid
+func scanIdentifier(o *OutputOptions) string {
+	if o.SqliteOutputOptions.ScanIdentifier == "" {
 		return generateUUID()
 	}
-	return s.config.OutputOptions.SqliteOutputOptions.ScanIdentifier
+	return o.SqliteOutputOptions.ScanIdentifier
 }
 
 // generateUUID generates new random uuid string
@@ -0,0 +1,63 @@
+package formatter
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+const (
+	// DefaultIPv4SubnetPrefix is a prefix length used to group IPv4 hosts into subnets
+	DefaultIPv4SubnetPrefix = 24
+	// DefaultIPv6SubnetPrefix is a prefix length used to group IPv6 hosts into subnets
+	DefaultIPv6SubnetPrefix = 64
+)
+
+// subnetPrefix returns IPv4 prefix length used to group hosts into subnets,
+// DefaultIPv4SubnetPrefix is used if it's not set
+func subnetPrefix(prefix int) int {
+	if prefix == 0 {
+		return DefaultIPv4SubnetPrefix
+	}
+	return prefix
+}
+
+// validateSubnetPrefix checks whether IPv4 prefix length is between 0 and 32, zero prefix is not set
+func validateSubnetPrefix(prefix int) error {
+	if prefix < 0 || prefix > 32 {
+		return fmt.Errorf("subnet prefix should be between 0 and 32: %d", prefix)
+	}
+	return nil
+}
+
+// hostSubnet returns a subnet (CIDR) of the first IP address of the host, IPv4 addresses use ipv4Prefix
+// and IPv6 addresses use DefaultIPv6SubnetPrefix. Empty string is returned if host has no IP address
+func hostSubnet(h *Host, ipv4Prefix int) string {
+	for i := range h.HostAddress {
+		addr, err := netip.ParseAddr(h.HostAddress[i].Address)
+		if err != nil {
+			// MAC addresses and other non-IP addresses are skipped
+			continue
+		}
+		prefix := ipv4Prefix
+		if addr.Is6() && !addr.Is4In6() {
+			prefix = DefaultIPv6SubnetPrefix
+		}
+		network, err := addr.Prefix(prefix)
+		if err != nil {
+			continue
+		}
+		return network.String()
+	}
+	return ""
+}
+
+// hostsBySubnet groups host indexes by their subnets (hostSubnet), hosts without IP address
+// are grouped under empty string key
+func hostsBySubnet(hosts []Host, ipv4Prefix int) map[string][]int {
+	groups := map[string][]int{}
+	for i := range hosts {
+		subnet := hostSubnet(&hosts[i], ipv4Prefix)
+		groups[subnet] = append(groups[subnet], i)
+	}
+	return groups
+}
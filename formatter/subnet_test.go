@@ -0,0 +1,95 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_subnetPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  int
+		want    int
+		wantErr bool
+	}{
+		{name: "Default prefix", prefix: 0, want: DefaultIPv4SubnetPrefix},
+		{name: "Custom prefix", prefix: 16, want: 16},
+		{name: "Host prefix", prefix: 32, want: 32},
+		{name: "Negative prefix", prefix: -1, want: -1, wantErr: true},
+		{name: "Prefix is too long", prefix: 33, want: 33, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subnetPrefix(tt.prefix); got != tt.want {
+				t.Errorf("subnetPrefix() = %v, want %v", got, tt.want)
+			}
+			if err := validateSubnetPrefix(tt.prefix); (err != nil) != tt.wantErr {
+				t.Errorf("validateSubnetPrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_hostSubnet(t *testing.T) {
+	tests := []struct {
+		name       string
+		addresses  []HostAddress
+		ipv4Prefix int
+		want       string
+	}{
+		{
+			name:       "No addresses",
+			addresses:  []HostAddress{},
+			ipv4Prefix: 24,
+			want:       "",
+		},
+		{
+			name:       "IPv4 /24",
+			addresses:  []HostAddress{{Address: "192.168.1.20", AddressType: "ipv4"}},
+			ipv4Prefix: 24,
+			want:       "192.168.1.0/24",
+		},
+		{
+			name:       "IPv4 /16 after MAC address",
+			addresses:  []HostAddress{{Address: "00:11:22:33:44:55", AddressType: "mac"}, {Address: "10.20.30.40", AddressType: "ipv4"}},
+			ipv4Prefix: 16,
+			want:       "10.20.0.0/16",
+		},
+		{
+			name:       "IPv6",
+			addresses:  []HostAddress{{Address: "2001:db8::1", AddressType: "ipv6"}},
+			ipv4Prefix: 24,
+			want:       "2001:db8::/64",
+		},
+		{
+			name:       "Wrong prefix",
+			addresses:  []HostAddress{{Address: "10.20.30.40", AddressType: "ipv4"}},
+			ipv4Prefix: 33,
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hostSubnet(&Host{HostAddress: tt.addresses}, tt.ipv4Prefix); got != tt.want {
+				t.Errorf("hostSubnet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hostsBySubnet(t *testing.T) {
+	hosts := []Host{
+		{HostAddress: []HostAddress{{Address: "10.0.0.1"}}},
+		{HostAddress: []HostAddress{{Address: "10.0.1.1"}}},
+		{HostAddress: []HostAddress{{Address: "10.0.0.2"}}},
+		{HostAddress: []HostAddress{}},
+	}
+	want := map[string][]int{
+		"10.0.0.0/24": {0, 2},
+		"10.0.1.0/24": {1},
+		"":            {3},
+	}
+	if got := hostsBySubnet(hosts, 24); !reflect.DeepEqual(got, want) {
+		t.Errorf("hostsBySubnet() = %v, want %v", got, want)
+	}
+}
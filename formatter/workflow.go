@@ -3,6 +3,7 @@ package formatter
 import (
 	"encoding/xml"
 	"fmt"
+	"io"
 	"log"
 	"os"
 )
@@ -30,6 +31,10 @@ func (w *MainWorkflow) SetConfig(c *Config) {
 func (w *MainWorkflow) SetOutputFile() {
 	if w.Config.OutputFile == "" {
 		w.Config.Writer = os.Stdout
+	} else if w.Config.OutputFormat.AtomicOutput() {
+		// Error has been checked before executing this function
+		f, _ := NewAtomicFile(string(w.Config.OutputFile))
+		w.Config.Writer = f
 	} else {
 		// Error has been checked before executing this function
 		f, _ := os.OpenFile(string(w.Config.OutputFile), os.O_CREATE|os.O_EXCL|os.O_WRONLY, os.ModePerm)
@@ -49,12 +54,15 @@ func (w *MainWorkflow) SetInputFile() {
 	w.Config.InputFileConfig.Source = inputFile
 }
 
-// prependConfigFilters prepends default filters to the filter expressions
-func (w *MainWorkflow) prependConfigFilters() {
+// filterExpressions returns filter expressions of the user with default filters appended,
+// the config is left untouched, so formatters see only the expressions that were passed
+func (w *MainWorkflow) filterExpressions() []string {
+	expressions := append([]string{}, w.Config.FilterExpressions...)
 	// A default filter for `skip-down-hosts` is applied
 	if w.Config.SkipDownHosts {
-		w.Config.FilterExpressions = append(w.Config.FilterExpressions, "Status.State == 'up'")
+		expressions = append(expressions, SkipDownHostsExpression)
 	}
+	return expressions
 }
 
 // Execute is the core of the application which executes required steps
@@ -66,20 +74,24 @@ func (w *MainWorkflow) Execute() (err error) {
 		return
 	}
 
-	filteredRun := NMAPRun
-	w.prependConfigFilters()
-
-	for _, expr := range w.Config.FilterExpressions {
+	expressions := w.filterExpressions()
+	for _, expr := range expressions {
 		log.Printf("filtering with expression: %s", expr)
-		filteredRun, err = filterExpr(filteredRun, expr)
-		if err != nil {
-			return fmt.Errorf("error filtering: %v", err)
-		}
 	}
+	filteredRun, err := FilterNMAPRun(NMAPRun, expressions)
+	if err != nil {
+		return fmt.Errorf("error filtering: %v", err)
+	}
+
+	return w.Format(filteredRun)
+}
 
+// Format formats NMAPRun with the output format & options from the config
+// and writes the result to the config writer
+func (w *MainWorkflow) Format(run NMAPRun) (err error) {
 	// Build template data with NMAPRun entry & various output options
 	templateData := TemplateData{
-		NMAPRun:       filteredRun,
+		NMAPRun:       run,
 		OutputOptions: w.Config.OutputOptions,
 	}
 
@@ -104,12 +116,29 @@ func (w *MainWorkflow) parse() (run NMAPRun, err error) {
 	if w.Config.InputFileConfig.Source == nil {
 		return run, fmt.Errorf("no input file is defined")
 	}
-	d := xml.NewDecoder(w.Config.InputFileConfig.Source)
-	_, err = d.Token()
-	if err != nil {
-		return
-	}
+	return ParseNMAPRun(w.Config.InputFileConfig.Source)
+}
 
-	err = d.Decode(&run)
-	return
+// ParseNMAPRun reads & unmarshalles nmap XML into NMAPRun struct
+func ParseNMAPRun(source io.Reader) (run NMAPRun, err error) {
+	d := xml.NewDecoder(source)
+	stylesheet := ""
+	for {
+		var token xml.Token
+		token, err = d.Token()
+		if err != nil {
+			return
+		}
+		switch t := token.(type) {
+		case xml.ProcInst:
+			// Stylesheet is kept to be able to re-emit nmap XML with the same header
+			if t.Target == "xml-stylesheet" {
+				stylesheet = string(t.Inst)
+			}
+		case xml.StartElement:
+			err = d.DecodeElement(&run, &t)
+			run.XMLStylesheet = stylesheet
+			return
+		}
+	}
 }
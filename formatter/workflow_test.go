@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -43,8 +44,10 @@ func TestMainWorkflow_parse(t *testing.T) {
 			w: &MainWorkflow{
 				Config: &Config{},
 			},
-			wantNMAPRun: NMAPRun{},
-			wantErr:     false,
+			wantNMAPRun: NMAPRun{
+				XMLStylesheet: `href="file:///usr/local/bin/../share/nmap/nmap.xsl" type="text/xsl"`,
+			},
+			wantErr: false,
 			fileContent: `<?xml version="1.0"?>
 			<?xml-stylesheet href="file:///usr/local/bin/../share/nmap/nmap.xsl" type="text/xsl"?>
 			<nmaprun></nmaprun>`,
@@ -183,6 +186,9 @@ func TestMainWorkflow_Execute(t *testing.T) {
 		fileName    string
 		fileContent string
 		before      func(file string, t *testing.T)
+		// wantContains and wantMissing are checked in the output file
+		wantContains []string
+		wantMissing  []string
 	}{
 		{
 			name: "Parse of the file has failed",
@@ -205,6 +211,42 @@ func TestMainWorkflow_Execute(t *testing.T) {
 			fileContent: `<?xml version="1.0"?>
 			<nmaprun></nmaprun>`,
 		},
+		{
+			name: "Skip down hosts",
+			w: &MainWorkflow{
+				Config: &Config{
+					OutputFormat:  CSVOutput,
+					SkipDownHosts: true,
+				},
+			},
+			wantErr:  false,
+			fileName: "main_workflow_Execute_6_test",
+			fileContent: `<?xml version="1.0"?>
+			<nmaprun>
+				<host><status state="up"/><address addr="10.0.0.1" addrtype="ipv4"/></host>
+				<host><status state="down"/><address addr="10.0.0.2" addrtype="ipv4"/></host>
+			</nmaprun>`,
+			wantContains: []string{"10.0.0.1 (up)"},
+			wantMissing:  []string{"10.0.0.2"},
+		},
+		{
+			name: "JSON envelope contains only filters of the user",
+			w: &MainWorkflow{
+				Config: &Config{
+					OutputFormat:      JSONOutput,
+					SkipDownHosts:     true,
+					FilterExpressions: []string{"true"},
+					OutputOptions: OutputOptions{
+						JSONOptions: JSONOutputOptions{Envelope: true},
+					},
+				},
+			},
+			wantErr:  false,
+			fileName: "main_workflow_Execute_7_test",
+			fileContent: `<?xml version="1.0"?>
+			<nmaprun></nmaprun>`,
+			wantContains: []string{`"SkipDownHosts":true,"FilterExpressions":["true"]}`},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -233,6 +275,24 @@ func TestMainWorkflow_Execute(t *testing.T) {
 			if err := tt.w.Execute(); (err != nil) != tt.wantErr {
 				t.Errorf("MainWorkflow.Execute() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if len(tt.wantContains) == 0 && len(tt.wantMissing) == 0 {
+				return
+			}
+			_ = tt.w.Config.Writer.Close()
+			output, err := os.ReadFile(string(tt.w.Config.OutputFile))
+			if err != nil {
+				t.Fatalf("could not read output file: %v", err)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("MainWorkflow.Execute() output does not contain %q, output = \n%s", want, output)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(string(output), missing) {
+					t.Errorf("MainWorkflow.Execute() output should not contain %q", missing)
+				}
+			}
 		})
 	}
 }